@@ -0,0 +1,411 @@
+package gospec
+
+import (
+	"crypto/md5" //nolint:gosec
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// stepRun holds the timing of a step executed as part of a test, i.e. of a beforeEach or an
+// `it` block, as a beforeEach block is shared by the tests which follow it.
+type stepRun struct {
+	startedAt time.Time
+	timeSpent time.Duration
+	// done reports whether the step returned, i.e. the test was not stopped by it, e.g. via
+	// t.FailNow or t.Skip.
+	done bool
+}
+
+func (r *stepRun) track(cb func()) {
+	r.startedAt = time.Now()
+	defer func() {
+		r.timeSpent = time.Since(r.startedAt)
+	}()
+	cb()
+	r.done = true
+}
+
+type allureResult struct {
+	UUID        string             `json:"uuid"`
+	HistoryID   string             `json:"historyId"`
+	FullName    string             `json:"fullName"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Status      string             `json:"status"`
+	Stage       string             `json:"stage"`
+	Start       int64              `json:"start"`
+	Stop        int64              `json:"stop"`
+	Labels      []allureLabel      `json:"labels"`
+	Parameters  []allureParameter  `json:"parameters"`
+	Steps       []allureStep       `json:"steps"`
+	Attachments []allureAttachment `json:"attachments"`
+}
+
+type allureStep struct {
+	Name        string             `json:"name"`
+	Status      string             `json:"status"`
+	Stage       string             `json:"stage"`
+	Start       int64              `json:"start"`
+	Stop        int64              `json:"stop"`
+	Steps       []allureStep       `json:"steps"`
+	Attachments []allureAttachment `json:"attachments"`
+}
+
+type allureContainer struct {
+	UUID     string   `json:"uuid"`
+	Name     string   `json:"name"`
+	Children []string `json:"children"`
+	Start    int64    `json:"start,omitempty"`
+	Stop     int64    `json:"stop,omitempty"`
+}
+
+type allureLabel struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type allureParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type allureAttachment struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+}
+
+type allureReport struct {
+	results    []*allureResult
+	containers []*allureContainer
+	// files are the contents of the attachments, by their file names.
+	files map[string][]byte
+}
+
+// attachments returns the references to the attachments, whose files get written along
+// with the results.
+func (r *allureReport) attachments(attachments []*attachment) []allureAttachment {
+	result := make([]allureAttachment, 0, len(attachments))
+	for _, a := range attachments {
+		source := newUUID() + "-attachment" + a.extension()
+		if r.files == nil {
+			r.files = map[string][]byte{}
+		}
+		r.files[source] = a.data
+		result = append(result, allureAttachment{Name: a.name, Source: source, Type: a.mimeType})
+	}
+	return result
+}
+
+// container adds a container for the results added after the index.
+func (r *allureReport) container(name string, from int) {
+	c := &allureContainer{UUID: newUUID(), Name: name, Children: []string{}}
+	for _, result := range r.results[from:] {
+		c.Children = append(c.Children, result.UUID)
+		if c.Start == 0 || (result.Start != 0 && result.Start < c.Start) {
+			c.Start = result.Start
+		}
+		if result.Stop > c.Stop {
+			c.Stop = result.Stop
+		}
+	}
+	r.containers = append(r.containers, c)
+}
+
+func (r *allureReport) write(dir string) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	for _, result := range r.results {
+		if err := os.WriteFile(filepath.Join(dir, result.UUID+"-result.json"), []byte(encodeJSON(result, "")), 0o600); err != nil {
+			return err
+		}
+	}
+	for _, c := range r.containers {
+		if err := os.WriteFile(filepath.Join(dir, c.UUID+"-container.json"), []byte(encodeJSON(c, "")), 0o600); err != nil {
+			return err
+		}
+	}
+	for source, data := range r.files {
+		if err := os.WriteFile(filepath.Join(dir, source), data, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (suite *SpecSuite) writeAllureResults() {
+	if suite.allureDir == "" {
+		return
+	}
+	if err := suite.allureReport().write(suite.allureDir); err != nil {
+		suite.t.Errorf("failed to write the allure results: %s", err)
+	}
+}
+
+func (fs *FeatureSuite) writeAllureResults() {
+	if fs.allureDir == "" {
+		return
+	}
+	if err := tree2(fs.nodes).allureReport().write(fs.allureDir); err != nil {
+		fs.t.Errorf("failed to write the allure results: %s", err)
+	}
+}
+
+// allureReport returns the results of the `it` blocks, whose steps are the beforeEach blocks
+// preceding them.
+func (suite *SpecSuite) allureReport() *allureReport {
+	report := &allureReport{}
+
+	// the results are grouped by the top level describe blocks
+	var (
+		from  int
+		first *step
+	)
+
+	for i, suite2 := range suite.suites {
+		if len(suite2) == 0 || suite2[len(suite2)-1].block != isIt {
+			continue
+		}
+
+		if suite2[0] != first && first != nil {
+			report.container(first.title, from)
+			from = len(report.results)
+		}
+		first = suite2[0]
+
+		it := suite2[len(suite2)-1]
+		status := statusPassed
+		if t := suite.testObjects[i]; t != nil && t.Skipped() {
+			status = statusSkipped
+		}
+		if t := suite.testObjects[i]; t != nil && t.Failed() {
+			status = statusFailed
+		}
+
+		var (
+			path  []string
+			steps = []allureStep{}
+			runs  = suite.runs[i]
+		)
+
+		for j, s := range suite2[:len(suite2)-1] {
+			switch s.block {
+			case isDescribe:
+				path = append(path, s.title)
+			case isBeforeEach:
+				steps = append(steps, allureStep{
+					Name:        "beforeEach",
+					Status:      allureStatus(runStatus(runs[j], status)),
+					Stage:       "finished",
+					Start:       milliseconds(runs[j].startedAt),
+					Stop:        milliseconds(runs[j].startedAt.Add(runs[j].timeSpent)),
+					Steps:       []allureStep{},
+					Attachments: []allureAttachment{},
+				})
+			case isIt:
+			}
+		}
+
+		var started, finished time.Time
+		for _, r := range runs {
+			if r.startedAt.IsZero() {
+				continue
+			}
+			if started.IsZero() {
+				started = r.startedAt
+			}
+			finished = r.startedAt.Add(r.timeSpent)
+		}
+
+		fullName := strings.Join(append(path, it.title), " / ")
+
+		report.results = append(report.results, &allureResult{
+			UUID:        newUUID(),
+			HistoryID:   historyID(fullName),
+			FullName:    fullName,
+			Name:        it.title,
+			Status:      allureStatus(status),
+			Stage:       "finished",
+			Start:       milliseconds(started),
+			Stop:        milliseconds(finished),
+			Labels:      allureLabels(path),
+			Parameters:  []allureParameter{},
+			Steps:       steps,
+			Attachments: report.attachments(withOutput(it.attachments, it.output)),
+		})
+	}
+
+	if first != nil {
+		report.container(first.title, from)
+	}
+
+	return report
+}
+
+// withOutput adds the captured output of a failed test to its attachments.
+func withOutput(attachments []*attachment, output string) []*attachment {
+	if output == "" {
+		return attachments
+	}
+	return append(attachments[:len(attachments):len(attachments)], &attachment{
+		name:     "output",
+		mimeType: "text/plain",
+		data:     []byte(output),
+	})
+}
+
+// runStatus returns the status of a step of a test, whereby the step which stopped the
+// test gets the status of the test.
+func runStatus(r stepRun, testStatus string) string {
+	switch {
+	case r.startedAt.IsZero():
+		return statusSkipped
+	case !r.done:
+		return testStatus
+	}
+	return statusPassed
+}
+
+// allureReport returns the results of the scenarios, whose steps are the given/when/then
+// steps, including the ones of the backgrounds.
+func (t tree2) allureReport() *allureReport {
+	report := &allureReport{}
+
+	for _, f := range t {
+		from := len(report.results)
+
+		for _, sc := range collectScenarios(f) {
+			s := sc.scenario.step
+
+			path := []string{f.step.title}
+			if sc.rule != nil {
+				path = append(path, sc.rule.step.title)
+			}
+
+			labels := append(allureLabels(path), allureLabel{Name: "feature", Value: f.step.title})
+			for _, tag := range sc.tags() {
+				labels = append(labels, allureLabel{Name: "tag", Value: strings.TrimPrefix(tag, "@")})
+			}
+
+			parameters := []allureParameter{}
+			name := s.title
+			if sc.outline != nil {
+				name = sc.outline.step.title
+				header := sc.examples.step.rows[0]
+				for i, value := range sc.examples.step.rows[sc.row] {
+					parameters = append(parameters, allureParameter{Name: header[i], Value: value})
+				}
+			}
+
+			at := s.startedAt
+			steps := []allureStep{}
+			for _, n := range sc.steps() {
+				r := sc.result(n)
+				if !r.startedAt.IsZero() {
+					at = r.startedAt
+				}
+				steps = append(steps, allureStep{
+					Name:        n.step.keyword() + " " + n.step.title,
+					Status:      allureStatus(r.status),
+					Stage:       "finished",
+					Start:       milliseconds(at),
+					Stop:        milliseconds(at.Add(r.timeSpent)),
+					Steps:       []allureStep{},
+					Attachments: report.attachments(r.attachments),
+				})
+				if !at.IsZero() {
+					at = at.Add(r.timeSpent)
+				}
+			}
+
+			fullName := strings.Join(append(path, name), " / ")
+			var values []string
+			for _, p := range parameters {
+				values = append(values, p.Value)
+			}
+
+			report.results = append(report.results, &allureResult{
+				UUID:        newUUID(),
+				HistoryID:   historyID(fullName + strings.Join(values, "|")),
+				FullName:    fullName,
+				Name:        s.title,
+				Description: s.description,
+				Status:      allureStatus(scenarioStatus(s)),
+				Stage:       "finished",
+				Start:       milliseconds(s.startedAt),
+				Stop:        milliseconds(s.startedAt.Add(s.timeSpent)),
+				Labels:      labels,
+				Parameters:  parameters,
+				Steps:       steps,
+				Attachments: report.attachments(withOutput(nil, s.output)),
+			})
+		}
+
+		report.container(f.step.title, from)
+	}
+
+	return report
+}
+
+// allureLabels returns the suite labels of a test, based on the titles of the blocks it is
+// defined in, along with the framework and language labels.
+func allureLabels(path []string) []allureLabel {
+	labels := []allureLabel{
+		{Name: "framework", Value: "gospec"},
+		{Name: "language", Value: "go"},
+	}
+	switch len(path) {
+	case 0:
+	case 1:
+		labels = append(labels, allureLabel{Name: "suite", Value: path[0]})
+	case 2:
+		labels = append(labels,
+			allureLabel{Name: "parentSuite", Value: path[0]},
+			allureLabel{Name: "suite", Value: path[1]},
+		)
+	default:
+		labels = append(labels,
+			allureLabel{Name: "parentSuite", Value: path[0]},
+			allureLabel{Name: "suite", Value: path[1]},
+			allureLabel{Name: "subSuite", Value: strings.Join(path[2:], " > ")},
+		)
+	}
+	return labels
+}
+
+// allureStatus returns the Allure status of a test or a step. The tests and the steps which
+// did not run, or have no step definition, are reported as skipped.
+func allureStatus(status string) string {
+	switch status {
+	case statusPassed, statusFailed:
+		return status
+	}
+	return statusSkipped
+}
+
+func milliseconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// historyID identifies a test across runs, so that Allure can show its history.
+func historyID(fullName string) string {
+	sum := md5.Sum([]byte(fullName)) //nolint:gosec
+	return hex.EncodeToString(sum[:])
+}
+
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
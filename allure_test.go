@@ -0,0 +1,274 @@
+package gospec
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+// readAllureResults reads the results written into the dir, without the generated ids and
+// the timings, sorted by their full names. The children of the containers are replaced with
+// the full names of the respective results.
+func readAllureResults(t *testing.T, dir string) ([]allureResult, []allureContainer) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		results    []allureResult
+		containers []allureContainer
+		names      = map[string]string{}
+	)
+
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case strings.HasSuffix(e.Name(), "-result.json"):
+			var r allureResult
+			if err := json.Unmarshal(b, &r); err != nil {
+				t.Fatal(err)
+			}
+			if r.Start == 0 || r.Stop < r.Start {
+				t.Errorf("unexpected timing of %q: %d - %d", r.FullName, r.Start, r.Stop)
+			}
+			names[r.UUID] = r.FullName
+			r.UUID, r.HistoryID, r.Start, r.Stop = "", "", 0, 0
+			for i := range r.Steps {
+				r.Steps[i].Start, r.Steps[i].Stop = 0, 0
+			}
+			results = append(results, r)
+		case strings.HasSuffix(e.Name(), "-container.json"):
+			var c allureContainer
+			if err := json.Unmarshal(b, &c); err != nil {
+				t.Fatal(err)
+			}
+			c.UUID, c.Start, c.Stop = "", 0, 0
+			containers = append(containers, c)
+		}
+	}
+
+	for _, c := range containers {
+		for i, child := range c.Children {
+			c.Children[i] = names[child]
+		}
+		sort.Strings(c.Children)
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].FullName < results[j].FullName })
+
+	return results, containers
+}
+
+func TestSpecSuiteAllureResults(t *testing.T) {
+	var (
+		dir = filepath.Join(t.TempDir(), "allure-results")
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithSpecSuite(t, func(s *SpecSuite) {
+			s.t = tm
+			describe, beforeEach, it := s.With(Output(&strings.Builder{}), AllureResults(dir)).API()
+
+			describe("Cart", func() {
+				beforeEach(func(t *T) {})
+
+				describe("when empty", func() {
+					it("has no items", func(t *T) {}, Only)
+					it("has no total", func(t *T) {})
+				})
+			})
+		})
+	}()
+
+	results, containers := readAllureResults(t, dir)
+
+	step := func(status string) allureStep {
+		return allureStep{Name: "beforeEach", Status: status, Stage: "finished", Steps: []allureStep{}, Attachments: []allureAttachment{}}
+	}
+
+	labels := []allureLabel{
+		{Name: "framework", Value: "gospec"},
+		{Name: "language", Value: "go"},
+		{Name: "parentSuite", Value: "Cart"},
+		{Name: "suite", Value: "when empty"},
+	}
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []allureResult{
+		{
+			FullName:    "Cart / when empty / has no items",
+			Name:        "has no items",
+			Status:      "passed",
+			Stage:       "finished",
+			Labels:      labels,
+			Parameters:  []allureParameter{},
+			Steps:       []allureStep{step("passed")},
+			Attachments: []allureAttachment{},
+		},
+		{
+			FullName:    "Cart / when empty / has no total",
+			Name:        "has no total",
+			Status:      "skipped",
+			Stage:       "finished",
+			Labels:      labels,
+			Parameters:  []allureParameter{},
+			Steps:       []allureStep{step("passed")},
+			Attachments: []allureAttachment{},
+		},
+	}, results)
+	assert.Equal(t, []allureContainer{{Name: "Cart", Children: []string{
+		"Cart / when empty / has no items",
+		"Cart / when empty / has no total",
+	}}}, containers)
+}
+
+func TestFeatureSuiteAllureResults(t *testing.T) {
+	var (
+		dir = t.TempDir()
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, background, scenario, given, when, then, _ := s.With(Output(&strings.Builder{}), AllureResults(dir)).API()
+			scenarioOutline := s.OutlineAPI()
+			rule := s.RuleAPI()
+
+			feature("Cart\nAs a shopper I want to collect items", func() {
+				background(func() {
+					given("an empty cart", func(t *T) {})
+				})
+
+				scenario("adding an item", func() {
+					when("an item is added", func(t *T) {})
+					then("the cart has 1 item", func(t *T) {})
+				}, "@smoke")
+
+				rule("Limits", func() {
+					scenarioOutline("adding <count> items", [][]string{
+						{"count"},
+						{"100"},
+					}, func(row map[string]string) {
+						when("<count> items are added", func(t *T) {})
+					})
+				})
+			})
+		})
+	}()
+
+	results, containers := readAllureResults(t, dir)
+
+	step := func(name string) allureStep {
+		return allureStep{Name: name, Status: "passed", Stage: "finished", Steps: []allureStep{}, Attachments: []allureAttachment{}}
+	}
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []allureResult{
+		{
+			FullName: "Cart / Limits / adding <count> items",
+			Name:     "adding 100 items",
+			Status:   "passed",
+			Stage:    "finished",
+			Labels: []allureLabel{
+				{Name: "framework", Value: "gospec"},
+				{Name: "language", Value: "go"},
+				{Name: "parentSuite", Value: "Cart"},
+				{Name: "suite", Value: "Limits"},
+				{Name: "feature", Value: "Cart"},
+			},
+			Parameters:  []allureParameter{{Name: "count", Value: "100"}},
+			Steps:       []allureStep{step("Given an empty cart"), step("When 100 items are added")},
+			Attachments: []allureAttachment{},
+		},
+		{
+			FullName: "Cart / adding an item",
+			Name:     "adding an item",
+			Status:   "passed",
+			Stage:    "finished",
+			Labels: []allureLabel{
+				{Name: "framework", Value: "gospec"},
+				{Name: "language", Value: "go"},
+				{Name: "suite", Value: "Cart"},
+				{Name: "feature", Value: "Cart"},
+				{Name: "tag", Value: "smoke"},
+			},
+			Parameters:  []allureParameter{},
+			Steps:       []allureStep{step("Given an empty cart"), step("When an item is added"), step("Then the cart has 1 item")},
+			Attachments: []allureAttachment{},
+		},
+	}, results)
+	assert.Equal(t, []allureContainer{{Name: "Cart", Children: []string{
+		"Cart / Limits / adding <count> items",
+		"Cart / adding an item",
+	}}}, containers)
+}
+
+func TestFeatureSuiteAllureResultsReportTheBackgroundStepsPerScenario(t *testing.T) {
+	var (
+		dir       = t.TempDir()
+		tm        = &mock{t: t}
+		scenarios int
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, background, scenario, given, when, _, _ := s.With(Output(&strings.Builder{}), AllureResults(dir)).API()
+
+			feature("Cart", func() {
+				background(func() {
+					given("an empty cart", func(t *T) {
+						scenarios++
+						Attach(t, "cart.txt", "text/plain", []byte(strings.Repeat("x", scenarios)))
+						if scenarios == 1 {
+							t.SkipNow()
+						}
+					})
+				})
+				scenario("adding an item", func() {
+					when("an item is added", func(t *T) {})
+				})
+				scenario("removing an item", func() {
+					when("an item is removed", func(t *T) {})
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+
+	results, _ := readAllureResults(t, dir)
+
+	var steps []string
+	for _, r := range results {
+		s := r.Steps[0]
+		for _, a := range s.Attachments {
+			data, err := os.ReadFile(filepath.Join(dir, a.Source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			steps = append(steps, r.Name+": "+s.Status+" "+string(data))
+		}
+	}
+
+	assert.Equal(t, []string{
+		"adding an item: skipped x",
+		"removing an item: passed xx",
+	}, steps)
+
+	attachments, _ := filepath.Glob(filepath.Join(dir, "*-attachment.txt"))
+	assert.Equal(t, 2, len(attachments))
+}
@@ -0,0 +1,71 @@
+package gospec
+
+import (
+	"testing"
+)
+
+// And is used to define an additional step of the same kind as the preceding one, so
+// that several preconditions, actions or assertions read naturally, e.g.
+// "Given ... And ...". It's rendered as `And`, in the color of the preceding step.
+type And func(title string, cb func(*testing.T))
+
+// But is the same as [And], but is rendered as `But`. It's meant for steps which
+// contrast the preceding one, e.g. "Then ... But ...".
+type But func(title string, cb func(*testing.T))
+
+// ParallelAnd is the same as [And] but is used in parallel tests.
+type ParallelAnd func(title string, cb func(*testing.T, *World))
+
+// ParallelBut is the same as [But] but is used in parallel tests.
+type ParallelBut func(title string, cb func(*testing.T, *World))
+
+// AndButAPI returns the [And] and [But] functions, which can be used along with the
+// ones returned by [FeatureSuite.API].
+func (fs *FeatureSuite) AndButAPI() (And, But) {
+	return func(title string, cb func(*testing.T)) {
+			fs.t.Helper()
+			fs.addStep(callerLocation(2), isAnd, title, cb, nil)
+		}, func(title string, cb func(*testing.T)) {
+			fs.t.Helper()
+			fs.addStep(callerLocation(2), isBut, title, cb, nil)
+		}
+}
+
+// ParallelAndButAPI returns the [ParallelAnd] and [ParallelBut] functions, which can be
+// used along with the ones returned by [FeatureSuite.ParallelAPI].
+func (fs *FeatureSuite) ParallelAndButAPI() (ParallelAnd, ParallelBut) {
+	return func(title string, cb func(*testing.T, *World)) {
+			fs.t.Helper()
+			fs.addStep(callerLocation(2), isAnd, title, nil, cb)
+		}, func(title string, cb func(*testing.T, *World)) {
+			fs.t.Helper()
+			fs.addStep(callerLocation(2), isBut, title, nil, cb)
+		}
+}
+
+// precedingStepKind returns the kind of the last given/when/then step defined in the
+// current scenario or background, which is inherited by the `And` and `But` steps.
+func (fs *FeatureSuite) precedingStepKind(conjunction string) featureStepKind {
+	fs.t.Helper()
+
+	for i := len(fs.currNode.children) - 1; i >= 0; i-- {
+		switch kind := fs.currNode.children[i].step.kind; kind { //nolint:exhaustive
+		case isGiven, isWhen, isThen:
+			return kind
+		}
+	}
+
+	fs.invalid = true
+	fs.t.Errorf("invalid position for `%s` function, it must follow a `Given`, `When` or `Then` step", conjunction)
+
+	return isGiven
+}
+
+// keyword returns the keyword the step is rendered with, i.e. `And` or `But` for
+// the steps which inherit the kind of the preceding step.
+func (s *featureStep) keyword() string {
+	if s.conjunction != "" {
+		return s.conjunction
+	}
+	return s.kind.keyword()
+}
@@ -0,0 +1,79 @@
+package gospec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestAndButSteps(t *testing.T) {
+	var (
+		out   bytes.Buffer
+		spec  *FeatureSuite
+		tm    = &mock{t: t}
+		calls []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			spec = s
+			s.t = tm
+			feature, background, scenario, given, when, then, _ := s.With(Output(&out, Colorful)).API()
+			and, but := s.AndButAPI()
+
+			feature("Checkout", func() {
+				background(func() {
+					given("an empty cart", func(t *T) { calls = append(calls, "given 0") })
+					and("a logged in user", func(t *T) { calls = append(calls, "and 0") })
+				})
+
+				scenario("paying for the cart", func() {
+					when("the user pays", func(t *T) { calls = append(calls, "when 1") })
+					then("the order is placed", func(t *T) { calls = append(calls, "then 1") })
+					but("no email is sent", func(t *T) { calls = append(calls, "but 1") })
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{"given 0", "and 0", "when 1", "then 1", "but 1"}, calls)
+	assert.Equal(t, isGiven, spec.suites[0][3].kind)
+	assert.Equal(t, isThen, spec.suites[0][7].kind)
+	assert.Equal(t, strings.Join([]string{
+		"\x1b[1m" + `Feature:` + "\x1b[0m" + ` Checkout`,
+		``,
+		`  ` + "\x1b[1m" + `Background:` + "\x1b[0m",
+		`    ` + "\x1b[0;32m✔\x1b[0m " + "\x1b[0;36m" + `Given` + "\x1b[0m" + ` an empty cart`,
+		`    ` + "\x1b[0;32m✔\x1b[0m " + "\x1b[0;36m" + `And` + "\x1b[0m" + ` a logged in user`,
+		``,
+		`  ` + "\x1b[0;32m✔\x1b[0m " + "\x1b[1m" + `Scenario:` + "\x1b[0m" + ` paying for the cart`,
+		`    ` + "\x1b[0;32m✔\x1b[0m " + "\x1b[0;32m" + `When` + "\x1b[0m" + ` the user pays`,
+		`    ` + "\x1b[0;32m✔\x1b[0m " + "\x1b[0;33m" + `Then` + "\x1b[0m" + ` the order is placed`,
+		`    ` + "\x1b[0;32m✔\x1b[0m " + "\x1b[0;33m" + `But` + "\x1b[0m" + ` no email is sent`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestAndButStepsMustFollowAStep(t *testing.T) {
+	tm := &mock{t: t}
+
+	WithFeatureSuite(t, func(s *FeatureSuite) {
+		s.t = tm
+		feature, _, scenario, _, _, _, _ := s.API()
+		and, _ := s.AndButAPI()
+
+		feature("Checkout", func() {
+			scenario("paying for the cart", func() {
+				and("the user pays", func(t *T) {})
+			})
+		})
+	})
+
+	assert.Equal(t, [][]any{
+		{"invalid position for `%s` function, it must follow a `Given`, `When` or `Then` step", "And"},
+	}, tm.calls)
+}
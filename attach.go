@@ -0,0 +1,161 @@
+package gospec
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// maxAttachmentSize is the size cap of an attachment, the data beyond it gets dropped.
+const maxAttachmentSize = 1 << 20
+
+// attachment is an artifact, e.g. a request/response dump or a screenshot, attached to an
+// `it` block or to a given/when/then step.
+type attachment struct {
+	name      string
+	mimeType  string
+	data      []byte
+	truncated bool
+}
+
+//nolint:gochecknoglobals
+var (
+	// attachTargets holds the attachments of the `it` blocks and the steps being run, by the
+	// *testing.T instances running them.
+	attachTargets = map[*testing.T]*[]*attachment{}
+	attachMu      sync.Mutex
+)
+
+// Attach attaches an artifact, e.g. a request/response dump, a log or a screenshot, to the
+// `it` block or the given/when/then step being run by t. The attachments are included in
+// the [HTML], [CucumberJSON] and [CucumberMessages] outputs and in the [AllureResults].
+// The data is capped at 1MiB, whereby the rest of it gets dropped.
+//
+// Example:
+//
+//	it("creates the order", func(t *testing.T) {
+//		res := post(t, "/orders", order)
+//		gospec.Attach(t, "response.json", "application/json", res)
+//	})
+func Attach(t *testing.T, name, mimeType string, data []byte) {
+	t.Helper()
+
+	attachMu.Lock()
+	defer attachMu.Unlock()
+
+	target, ok := attachTargets[t]
+	if !ok {
+		t.Errorf("invalid position for `Attach` function, it must be called inside an `It` block or a `Given`, `When` or `Then` step")
+		return
+	}
+
+	a := &attachment{name: name, mimeType: mimeType, data: data}
+	if len(data) > maxAttachmentSize {
+		a.data, a.truncated = data[:maxAttachmentSize], true
+		t.Logf("the attachment %q was truncated to %d bytes", name, maxAttachmentSize)
+	}
+
+	*target = append(*target, a)
+}
+
+// attachTo makes the [Attach] calls on t add to the attachments, until the returned function
+// gets called.
+func attachTo(t *testing.T, attachments *[]*attachment) func() {
+	attachMu.Lock()
+	defer attachMu.Unlock()
+
+	attachTargets[t] = attachments
+
+	return func() {
+		attachMu.Lock()
+		defer attachMu.Unlock()
+		delete(attachTargets, t)
+	}
+}
+
+func (a *attachment) base64() string {
+	return base64.StdEncoding.EncodeToString(a.data)
+}
+
+func (a *attachment) dataURI() string {
+	return "data:" + a.mimeType + ";base64," + a.base64()
+}
+
+// extension returns the file extension of the attachment, based on its name or, when the
+// name has none, on its MIME type.
+func (a *attachment) extension() string {
+	if ext := filepath.Ext(a.name); ext != "" {
+		return ext
+	}
+	if exts, _ := mime.ExtensionsByType(a.mimeType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// fileWriter is an output which might be a file, e.g. an [os.File].
+type fileWriter interface {
+	Name() string
+	Stat() (os.FileInfo, error)
+}
+
+// saveAttachments writes the attachments of the report nodes next to the output file, into
+// a `<name>-attachments` directory, so that the report can link to them instead of
+// embedding them. The outputs which are not regular files, e.g. [os.Stdout], or are
+// verified, keep them embedded.
+func (o *output1) saveAttachments(nodes []*reportNode) error {
+	f, ok := o.out.(fileWriter)
+	if !ok || o.verify {
+		return nil
+	}
+
+	if info, err := f.Stat(); err != nil || !info.Mode().IsRegular() {
+		return nil //nolint:nilerr
+	}
+
+	name := f.Name()
+	dir := strings.TrimSuffix(name, filepath.Ext(name)) + "-attachments"
+
+	var (
+		index int
+		save  func(n *reportNode) error
+	)
+
+	save = func(n *reportNode) error {
+		for _, a := range n.attachments {
+			if index == 0 {
+				if err := os.MkdirAll(dir, 0o750); err != nil {
+					return err
+				}
+			}
+			index++
+			file := fmt.Sprintf("%d-%s", index, filepath.Base(a.name))
+			if filepath.Ext(file) == "" {
+				file += a.attachment.extension()
+			}
+			if err := os.WriteFile(filepath.Join(dir, file), a.attachment.data, 0o600); err != nil {
+				return err
+			}
+			a.href = filepath.Base(dir) + "/" + file
+		}
+		for _, c := range n.children {
+			if err := save(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, n := range nodes {
+		if err := save(n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package gospec
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestSpecAttachments(t *testing.T) {
+	var (
+		out bytes.Buffer
+		dir = t.TempDir()
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithSpecSuite(t, func(s *SpecSuite) {
+			s.t = tm
+			describe, beforeEach, it := s.With(Output(&out, HTML), AllureResults(dir)).API()
+
+			describe("Orders API", func() {
+				beforeEach(func(t *T) {
+					Attach(t, "request.json", "application/json", []byte(`{"id":1}`))
+				})
+
+				it("creates an order", func(t *T) {
+					Attach(t, "response", "text/plain", []byte("created"))
+				})
+
+				it("dumps big payloads", func(t *T) {
+					Attach(t, "payload.bin", "application/octet-stream", make([]byte, maxAttachmentSize+1))
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, true, strings.Contains(out.String(), strings.Join([]string{
+		`<ul class="attachments">`,
+		`<li><a href="data:application/json;base64,eyJpZCI6MX0=" download="request.json" type="application/json">request.json</a></li>`,
+		`<li><a href="data:text/plain;base64,Y3JlYXRlZA==" download="response" type="text/plain">response</a></li>`,
+		`</ul>`,
+	}, "\n")))
+	assert.Equal(t, true, strings.Contains(out.String(), `download="payload.bin" type="application/octet-stream">payload.bin</a> (truncated)</li>`))
+
+	results, _ := readAllureResults(t, dir)
+	assert.Equal(t, 2, len(results))
+
+	var names, contents []string
+	for _, r := range results {
+		for _, a := range r.Attachments {
+			data, err := os.ReadFile(filepath.Join(dir, a.Source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, a.Name)
+			contents = append(contents, strings.TrimRight(string(data[:min(len(data), 8)]), "\x00"))
+		}
+	}
+
+	assert.Equal(t, []string{"request.json", "response", "request.json", "payload.bin"}, names)
+	assert.Equal(t, []string{`{"id":1}`, "created", `{"id":1}`, ""}, contents)
+}
+
+func TestFeatureAttachments(t *testing.T) {
+	var (
+		nodes tree2
+		tm    = &mock{t: t}
+		name  = filepath.Join(t.TempDir(), "report.html")
+	)
+
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, given, _, then, _ := s.With(Output(f, HTML)).API()
+
+			feature("Orders API", func() {
+				scenario("creating an order", func() {
+					given("an order", func(t *T) {})
+					then("the order is created", func(t *T) {
+						Attach(t, "response.json", "application/json", []byte(`{"id":1}`))
+					})
+				})
+			})
+
+			nodes = s.nodes
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+
+	report, _ := os.ReadFile(name)
+	assert.Equal(t, true, strings.Contains(string(report),
+		`<li><a href="report-attachments/1-response.json" download="response.json" type="application/json">response.json</a></li>`))
+
+	saved, _ := os.ReadFile(filepath.Join(filepath.Dir(name), "report-attachments", "1-response.json"))
+	assert.Equal(t, `{"id":1}`, string(saved))
+
+	assert.Equal(t, true, strings.Contains(nodes.cucumberJSON(), strings.Join([]string{
+		`            "embeddings": [`,
+		`              {`,
+		`                "mime_type": "application/json",`,
+		`                "data": "eyJpZCI6MX0=",`,
+		`                "name": "response.json"`,
+		`              }`,
+		`            ]`,
+	}, "\n")))
+	assert.Equal(t, true, strings.Contains(nodes.cucumberMessages(),
+		`"body":"eyJpZCI6MX0=","contentEncoding":"BASE64","fileName":"response.json","mediaType":"application/json"`))
+}
+
+func TestAttachmentsAreEmbeddedWhenTheOutputIsNotARegularFile(t *testing.T) {
+	var (
+		tm     = &mock{t: t}
+		report = make(chan string, 1)
+	)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+
+	go func() {
+		var sb strings.Builder
+		_, _ = io.Copy(&sb, r)
+		report <- sb.String()
+	}()
+
+	func() {
+		WithSpecSuite(t, func(s *SpecSuite) {
+			s.t = tm
+			describe, _, it := s.With(Output(w, HTML)).API()
+
+			describe("Orders API", func() {
+				it("creates an order", func(t *T) {
+					Attach(t, "response", "text/plain", []byte("created"))
+				})
+			})
+		})
+	}()
+
+	_ = w.Close()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, true, strings.Contains(<-report,
+		`<li><a href="data:text/plain;base64,Y3JlYXRlZA==" download="response" type="text/plain">response</a></li>`))
+
+	_, err = os.Stat(strings.TrimSuffix(w.Name(), filepath.Ext(w.Name())) + "-attachments")
+	assert.Equal(t, true, os.IsNotExist(err))
+}
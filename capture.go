@@ -0,0 +1,276 @@
+package gospec
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// capturer redirects the process-wide outputs, i.e. [os.Stdout], [os.Stderr] and the
+// standard logger, into the buffer of the spec being run. The output written while no spec
+// is running goes to the original outputs, at the latest once the next spec starts or the
+// suites are done. As the process-wide outputs can't tell which of the specs running in
+// parallel wrote to them, their output goes to the original outputs as well while more than
+// one spec is running. Each spec has its own sink though, see [World.SlogHandler].
+type capturer struct {
+	mu      sync.Mutex
+	users   int
+	targets map[*bytes.Buffer]bool
+	stdout  *captureFile
+	stderr  *captureFile
+	log     io.Writer
+}
+
+// captureFile is a temporary file replacing one of the standard outputs. The data written
+// into it gets copied into the buffers of the running specs whenever the set of the running
+// specs changes, see [capturer.flush], so the output of a spec is complete once it's done.
+type captureFile struct {
+	original *os.File
+	f        *os.File
+	// offset is the size of the data copied out of the file so far.
+	offset int64
+}
+
+//nolint:gochecknoglobals
+var capture = &capturer{targets: map[*bytes.Buffer]bool{}}
+
+// install starts redirecting the outputs, unless they are already redirected by another suite.
+func (c *capturer) install() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.users++
+	if c.users > 1 {
+		return nil
+	}
+
+	stdout, err := newCaptureFile(os.Stdout)
+	if err != nil {
+		c.users--
+		return err
+	}
+	stderr, err := newCaptureFile(os.Stderr)
+	if err != nil {
+		c.users--
+		stdout.close()
+		return err
+	}
+
+	c.stdout, c.stderr = stdout, stderr
+	os.Stdout, os.Stderr = stdout.f, stderr.f
+
+	c.log = log.Writer()
+	log.SetOutput(captureWriter{fallback: c.log})
+
+	return nil
+}
+
+// uninstall restores the outputs, once the last of the suites using them is done.
+func (c *capturer) uninstall() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.users--
+	if c.users > 0 {
+		return
+	}
+
+	c.flush()
+
+	os.Stdout, os.Stderr = c.stdout.original, c.stderr.original
+	log.SetOutput(c.log)
+	c.stdout.close()
+	c.stderr.close()
+	c.stdout, c.stderr = nil, nil
+}
+
+func newCaptureFile(original *os.File) (*captureFile, error) {
+	f, err := os.CreateTemp("", "gospec-output-*")
+	if err != nil {
+		return nil, err
+	}
+	return &captureFile{original: original, f: f}, nil
+}
+
+func (p *captureFile) close() {
+	_ = p.f.Close()
+	_ = os.Remove(p.f.Name())
+}
+
+// flush copies the data written into the files since the last flush into the buffer of
+// the running spec, or into the original outputs unless a single spec is running. As the data is
+// in the files once the writes return, it holds all of the output written before the flush.
+// It must be called with c.mu held.
+func (c *capturer) flush() {
+	buf := make([]byte, 32*1024)
+	for _, p := range []*captureFile{c.stdout, c.stderr} {
+		if p == nil {
+			continue
+		}
+		for {
+			n, err := p.f.ReadAt(buf, p.offset)
+			p.offset += int64(n)
+			if target := c.target(); target != nil {
+				target.Write(buf[:n])
+			} else {
+				_, _ = p.original.Write(buf[:n])
+			}
+			if err != nil {
+				break
+			}
+		}
+	}
+}
+
+// target returns the buffer of the running spec, or nil unless a single spec is running.
+// It must be called with c.mu held.
+func (c *capturer) target() *bytes.Buffer {
+	if len(c.targets) != 1 {
+		return nil
+	}
+	for buf := range c.targets {
+		return buf
+	}
+	return nil
+}
+
+// write copies the data into the buffer of the running spec, or writes it to the fallback
+// output unless a single spec is running.
+func (c *capturer) write(data []byte, fallback io.Writer) {
+	c.writeTo(nil, data, fallback)
+}
+
+// writeTo copies the data into the buffer of a running spec, or writes it to the fallback
+// output when the spec is not running anymore. The nil buffer stands for the running spec,
+// when a single one is running.
+func (c *capturer) writeTo(buf *bytes.Buffer, data []byte, fallback io.Writer) {
+	if len(data) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	// the data written into the files so far precedes this one
+	c.flush()
+	if buf == nil {
+		buf = c.target()
+	}
+	if buf == nil || !c.targets[buf] {
+		c.mu.Unlock()
+		_, _ = fallback.Write(data)
+		return
+	}
+
+	buf.Write(data)
+	c.mu.Unlock()
+}
+
+// begin starts capturing the output of a spec.
+func (c *capturer) begin() *bytes.Buffer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.flush()
+
+	buf := &bytes.Buffer{}
+	c.targets[buf] = true
+
+	return buf
+}
+
+// end stops capturing the output of a spec, and returns the captured output.
+func (c *capturer) end(buf *bytes.Buffer) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.flush()
+	delete(c.targets, buf)
+
+	return buf.String()
+}
+
+// captureWriter writes into the buffer of the running spec, or into the fallback output
+// unless a single spec is running.
+type captureWriter struct {
+	fallback io.Writer
+}
+
+func (w captureWriter) Write(p []byte) (int, error) {
+	capture.write(p, w.fallback)
+	return len(p), nil
+}
+
+// SlogHandler returns a text [slog.Handler] whose records get captured along with the rest
+// of the output of the specs, when the [CaptureOutput] option is set. Otherwise, the records
+// are written to [os.Stderr]. It can be set as the default handler, e.g.
+//
+//	slog.SetDefault(slog.New(gospec.SlogHandler(nil)))
+//
+// or be passed to the code under test. Like the rest of the process-wide outputs, it can't
+// tell the specs running in parallel apart, use [World.SlogHandler] for them.
+func SlogHandler(opts *slog.HandlerOptions) slog.Handler {
+	return slog.NewTextHandler(captureWriter{fallback: originalStderr{}}, opts)
+}
+
+// originalStderr writes to [os.Stderr], i.e. to the original one when the output is being
+// captured.
+type originalStderr struct{}
+
+func (originalStderr) Write(p []byte) (int, error) {
+	capture.mu.Lock()
+	w := os.Stderr
+	if capture.stderr != nil {
+		w = capture.stderr.original
+	}
+	capture.mu.Unlock()
+	return w.Write(p)
+}
+
+// worldWriter writes into the buffer of the spec the World belongs to, or into the original
+// stderr when the spec's output is not being captured.
+type worldWriter struct {
+	w *World
+}
+
+func (w worldWriter) Write(p []byte) (int, error) {
+	if w.w.output == nil {
+		return originalStderr{}.Write(p)
+	}
+	capture.writeTo(w.w.output, p, originalStderr{})
+	return len(p), nil
+}
+
+// captureOutput starts capturing the output of a spec, and returns the function which
+// stops it. The output is kept only when the spec failed.
+func captureOutput(t *testing.T, w *World, output *string) func() {
+	buf := capture.begin()
+	w.output = buf
+	return func() {
+		captured := capture.end(buf)
+		if t.Failed() {
+			*output = strings.TrimRight(captured, "\n")
+		}
+	}
+}
+
+// writeCapturedOutput writes the output captured while running a failed spec, under it.
+func writeCapturedOutput(sb *strings.Builder, indent int, output *output1, captured string) {
+	if captured == "" {
+		return
+	}
+
+	prefix := strings.Repeat(output.indentStep, indent)
+	header := "captured output:"
+	if output.colorful {
+		header = gray + header + noColor
+	}
+
+	sb.WriteString(prefix + header + "\n")
+	for _, l := range strings.Split(captured, "\n") {
+		sb.WriteString(strings.TrimRight(prefix+output.indentStep+l, " \t") + "\n")
+	}
+}
@@ -0,0 +1,166 @@
+package gospec
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestCaptureOutput(t *testing.T) {
+	if err := capture.install(); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := log.Flags()
+	log.SetFlags(0)
+	defer log.SetFlags(flags)
+
+	logger := slog.New(SlogHandler(&slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	buf := capture.begin()
+	fmt.Println("to stdout")
+	fmt.Fprintln(os.Stderr, "to stderr")
+	log.Print("via log")
+	logger.Info("via slog", "items", 2)
+	captured := capture.end(buf)
+
+	capture.uninstall()
+
+	assert.Equal(t, strings.Join([]string{
+		`to stdout`,
+		`to stderr`,
+		`via log`,
+		`level=INFO msg="via slog" items=2`,
+		``,
+	}, "\n"), sortLines(captured))
+}
+
+// sortLines sorts the lines written to stdout and stderr, as the order of the writes to
+// different outputs is not preserved.
+func sortLines(s string) string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	order := []string{"to stdout", "to stderr", "via log", "level=INFO"}
+	var sorted []string
+	for _, prefix := range order {
+		for _, l := range lines {
+			if strings.HasPrefix(l, prefix) {
+				sorted = append(sorted, l)
+			}
+		}
+	}
+	return strings.Join(sorted, "\n") + "\n"
+}
+
+func TestCaptureOutputKeepsZeroBytes(t *testing.T) {
+	if err := capture.install(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := capture.begin()
+	fmt.Print("a\x00b\x00\x00c")
+	captured := capture.end(buf)
+
+	buf = capture.begin()
+	fmt.Print("d")
+	next := capture.end(buf)
+
+	capture.uninstall()
+
+	assert.Equal(t, "a\x00b\x00\x00c", captured)
+	assert.Equal(t, "d", next)
+}
+
+func TestCaptureOutputOfParallelSpecs(t *testing.T) {
+	if err := capture.install(); err != nil {
+		t.Fatal(err)
+	}
+
+	w1, w2 := newWorld(), newWorld()
+	w1.output, w2.output = capture.begin(), capture.begin()
+
+	noTime := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}
+	slog.New(w1.SlogHandler(noTime)).Info("first")
+	slog.New(w2.SlogHandler(noTime)).Info("second")
+	log.Print("written while both are running")
+
+	captured1, captured2 := capture.end(w1.output), capture.end(w2.output)
+
+	capture.uninstall()
+
+	assert.Equal(t, "level=INFO msg=first\n", captured1)
+	assert.Equal(t, "level=INFO msg=second\n", captured2)
+}
+
+func TestCapturedOutputOfPassingSpecsIsDiscarded(t *testing.T) {
+	var (
+		out bytes.Buffer
+		tm  = &mock{t: t}
+		it1 *step
+	)
+
+	func() {
+		WithSpecSuite(t, func(s *SpecSuite) {
+			s.t = tm
+			describe, _, it := s.With(Output(&out), CaptureOutput()).API()
+
+			describe("Cart", func() {
+				it("is empty", func(t *T) {
+					fmt.Println("checking the cart")
+				})
+			})
+
+			it1 = s.suites[0][1]
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, "", it1.output)
+	assert.Equal(t, "Cart\n  ✔ is empty\n\n", out.String())
+}
+
+func TestCapturedOutputIsShownUnderFailedSpecs(t *testing.T) {
+	failed := &mock{t: t, calls: [][]any{{"failed"}}}
+
+	nodes := tree{
+		{
+			step: &step{block: isDescribe, title: "Cart"},
+			children: []*node{
+				{step: &step{block: isIt, title: "is empty", t: failed, output: "checking the cart\n\tfound 1 item"}},
+			},
+		},
+	}
+	suite := &SpecSuite{testObjects: []*testing.T{t}}
+
+	assert.Equal(t, strings.Join([]string{
+		`Cart`,
+		`  ⨯ is empty`,
+		`    captured output:`,
+		`      checking the cart`,
+		`      	found 1 item`,
+		``,
+		``,
+	}, "\n"), nodes.String(&output1{indentStep: indentTwoSpaces}, suite))
+
+	report := htmlReport(&output1{}, htmlReportTitle, nodes.report(suite))
+	assert.Equal(t, true, strings.Contains(report, "<pre class=\"output\">checking the cart\n\tfound 1 item</pre>"))
+}
@@ -0,0 +1,694 @@
+package gospec
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cucumberScenario is an executed scenario, along with the nodes it inherits the steps
+// and the tags of. For the scenarios of an outline, the row is the index of the example
+// row, starting from 1, as the row 0 is the header.
+type cucumberScenario struct {
+	feature     *node2
+	rule        *node2
+	backgrounds []*node2
+	outline     *node2
+	examples    *node2
+	row         int
+	scenario    *node2
+}
+
+func collectScenarios(feature *node2) []cucumberScenario {
+	var (
+		scenarios []cucumberScenario
+		collect   func(parent, rule *node2, backgrounds []*node2)
+	)
+
+	collect = func(parent, rule *node2, backgrounds []*node2) {
+		for _, c := range parent.children {
+			switch c.step.kind { //nolint:exhaustive
+			case isBackground:
+				backgrounds = append(append([]*node2{}, backgrounds...), c)
+			case isScenario:
+				scenarios = append(scenarios, cucumberScenario{
+					feature: feature, rule: rule, backgrounds: backgrounds, scenario: c,
+				})
+			case isScenarioOutline:
+				for _, e := range c.children {
+					if e.step.kind != isExamples {
+						continue
+					}
+					for i, row := range e.children {
+						scenarios = append(scenarios, cucumberScenario{
+							feature: feature, rule: rule, backgrounds: backgrounds,
+							outline: c, examples: e, row: i + 1, scenario: row,
+						})
+					}
+				}
+			case isRule:
+				collect(c, c, backgrounds)
+			}
+		}
+	}
+
+	collect(feature, nil, nil)
+
+	return scenarios
+}
+
+// tags returns the tags of the scenario, along with the inherited ones.
+func (sc cucumberScenario) tags() []string {
+	var tags []string
+	for _, n := range []*node2{sc.feature, sc.rule, sc.scenario} {
+		if n == nil {
+			continue
+		}
+		for _, tag := range n.step.tags {
+			if !containsString(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// steps returns the steps the scenario ran, including the ones of its backgrounds.
+func (sc cucumberScenario) steps() []*node2 {
+	var steps []*node2
+	for _, b := range sc.backgrounds {
+		steps = append(steps, stepNodes(b)...)
+	}
+	return append(steps, stepNodes(sc.scenario)...)
+}
+
+// stepNodes returns the given/when/then steps of a scenario or a background.
+func stepNodes(n *node2) []*node2 {
+	var steps []*node2
+	for _, c := range n.children {
+		if c.step.kind == isGiven || c.step.kind == isWhen || c.step.kind == isThen {
+			steps = append(steps, c)
+		}
+	}
+	return steps
+}
+
+// stepArgument returns the data table or the doc string of a step, if it has one.
+func stepArgument(n *node2) ([][]string, *DocString) {
+	for _, c := range n.children {
+		switch c.step.kind { //nolint:exhaustive
+		case isTable:
+			return c.step.rows, nil
+		case isDocString:
+			return nil, c.step.docString
+		}
+	}
+	return nil, nil
+}
+
+// result returns the result of a step in the scenario, whereby the steps which did not
+// run are reported as skipped.
+func (sc cucumberScenario) result(n *node2) *featureStepRun {
+	if r := sc.scenario.step.runOf(n.step); r != nil && r.status != "" {
+		return r
+	}
+	return &featureStepRun{status: statusSkipped}
+}
+
+// attachments returns the attachments of a step in the scenario. The output captured while
+// running a failed scenario is attached to its failed step, or to its last step when none
+// of them failed, e.g. when a hook failed the scenario.
+func (sc cucumberScenario) attachments(n *node2) []*attachment {
+	if n != sc.outputStep() {
+		return sc.result(n).attachments
+	}
+	return withOutput(sc.result(n).attachments, sc.scenario.step.output)
+}
+
+func (sc cucumberScenario) outputStep() *node2 {
+	steps := sc.steps()
+	for _, n := range steps {
+		if sc.result(n).status == statusFailed {
+			return n
+		}
+	}
+	if len(steps) == 0 {
+		return nil
+	}
+	return steps[len(steps)-1]
+}
+
+func cucumberID(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), "-"))
+}
+
+func relativePath(file string) string {
+	return strings.TrimPrefix(file, basePath)
+}
+
+type cucumberJSONFeature struct {
+	URI         string                `json:"uri"`
+	ID          string                `json:"id"`
+	Keyword     string                `json:"keyword"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Line        int                   `json:"line"`
+	Tags        []cucumberJSONTag     `json:"tags,omitempty"`
+	Elements    []cucumberJSONElement `json:"elements"`
+}
+
+type cucumberJSONTag struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+}
+
+type cucumberJSONElement struct {
+	ID          string             `json:"id,omitempty"`
+	Keyword     string             `json:"keyword"`
+	Type        string             `json:"type"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Line        int                `json:"line"`
+	Tags        []cucumberJSONTag  `json:"tags,omitempty"`
+	Steps       []cucumberJSONStep `json:"steps"`
+}
+
+type cucumberJSONStep struct {
+	Keyword   string                 `json:"keyword"`
+	Name      string                 `json:"name"`
+	Line      int                    `json:"line"`
+	Rows      []cucumberJSONRow      `json:"rows,omitempty"`
+	DocString *cucumberJSONDocString `json:"doc_string,omitempty"`
+	Match     cucumberJSONMatch      `json:"match"`
+	Result    cucumberJSONResult     `json:"result"`
+	// Embeddings are the attachments of the step, with their data base64 encoded.
+	Embeddings []cucumberJSONEmbedding `json:"embeddings,omitempty"`
+}
+
+type cucumberJSONEmbedding struct {
+	MimeType string `json:"mime_type"`
+	Data     string `json:"data"`
+	Name     string `json:"name,omitempty"`
+}
+
+type cucumberJSONRow struct {
+	Cells []string `json:"cells"`
+}
+
+type cucumberJSONDocString struct {
+	ContentType string `json:"content_type,omitempty"`
+	Value       string `json:"value"`
+}
+
+type cucumberJSONMatch struct {
+	Location string `json:"location"`
+}
+
+type cucumberJSONResult struct {
+	Status string `json:"status"`
+	// Duration is in nanoseconds.
+	Duration int64 `json:"duration,omitempty"`
+}
+
+// cucumberJSON renders the features in the cucumber-json format, whereby the steps of the
+// backgrounds are reported as a background element preceding each of the scenarios.
+func (t tree2) cucumberJSON() string {
+	features := make([]cucumberJSONFeature, 0, len(t))
+
+	for _, f := range t {
+		feature := cucumberJSONFeature{
+			URI:         relativePath(f.step.file),
+			ID:          cucumberID(f.step.title),
+			Keyword:     f.step.kind.keyword(),
+			Name:        f.step.title,
+			Description: f.step.description,
+			Line:        f.step.lineNo,
+			Tags:        cucumberJSONTags(f.step.tags, f.step.lineNo),
+			Elements:    []cucumberJSONElement{},
+		}
+
+		for _, sc := range collectScenarios(f) {
+			var background []*node2
+			for _, b := range sc.backgrounds {
+				background = append(background, stepNodes(b)...)
+			}
+			if len(background) > 0 {
+				b := sc.backgrounds[0]
+				feature.Elements = append(feature.Elements, cucumberJSONElement{
+					Keyword:     b.step.kind.keyword(),
+					Type:        "background",
+					Name:        b.step.title,
+					Description: b.step.description,
+					Line:        b.step.lineNo,
+					Steps:       cucumberJSONSteps(sc, background),
+				})
+			}
+
+			s := sc.scenario.step
+			element := cucumberJSONElement{
+				ID:          feature.ID + ";" + cucumberID(s.title),
+				Keyword:     s.kind.keyword(),
+				Type:        "scenario",
+				Name:        s.title,
+				Description: s.description,
+				Line:        s.lineNo,
+				Tags:        cucumberJSONTags(sc.tags(), s.lineNo),
+				Steps:       cucumberJSONSteps(sc, stepNodes(sc.scenario)),
+			}
+			if sc.outline != nil {
+				// the id of an example row is made of its position in the examples table,
+				// whereby the header is the first one
+				element.ID = feature.ID + ";" + cucumberID(sc.outline.step.title) + ";;" + strconv.Itoa(sc.row+1)
+				element.Keyword = sc.outline.step.kind.keyword()
+			}
+			feature.Elements = append(feature.Elements, element)
+		}
+
+		features = append(features, feature)
+	}
+
+	return encodeJSON(features, "  ")
+}
+
+// encodeJSON encodes the value as JSON followed by a newline, leaving the HTML characters,
+// e.g. the `<column>` placeholders of the outlines, unescaped.
+func encodeJSON(v any, indent string) string {
+	var sb strings.Builder
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	_ = enc.Encode(v)
+	return sb.String()
+}
+
+func cucumberJSONTags(tags []string, lineNo int) []cucumberJSONTag {
+	result := make([]cucumberJSONTag, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, cucumberJSONTag{Name: tag, Line: lineNo})
+	}
+	return result
+}
+
+// cucumberJSONSteps returns the steps of the scenario, or of its backgrounds, along with
+// their results in the scenario.
+func cucumberJSONSteps(sc cucumberScenario, nodes []*node2) []cucumberJSONStep {
+	steps := make([]cucumberJSONStep, 0, len(nodes))
+	for _, n := range nodes {
+		s, r := n.step, sc.result(n)
+		step := cucumberJSONStep{
+			Keyword: s.keyword() + " ",
+			Name:    s.title,
+			Line:    s.lineNo,
+			Match:   cucumberJSONMatch{Location: fmt.Sprintf("%s:%d", relativePath(s.file), s.lineNo)},
+			Result:  cucumberJSONResult{Status: r.status, Duration: r.timeSpent.Nanoseconds()},
+		}
+
+		rows, doc := stepArgument(n)
+		for _, r := range rows {
+			step.Rows = append(step.Rows, cucumberJSONRow{Cells: r})
+		}
+		if doc != nil {
+			step.DocString = &cucumberJSONDocString{ContentType: doc.ContentType, Value: doc.Content}
+		}
+		for _, a := range sc.attachments(n) {
+			step.Embeddings = append(step.Embeddings, cucumberJSONEmbedding{MimeType: a.mimeType, Data: a.base64(), Name: a.name})
+		}
+
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// object is a JSON object of a Cucumber message. Its keys get sorted when encoded, which
+// keeps the messages stable.
+type object = map[string]any
+
+// cucumberMessages writes the Cucumber messages of a run, as newline delimited JSON.
+type cucumberMessages struct {
+	sb     strings.Builder
+	nextID int
+	// astIDs holds the ids of the Gherkin document nodes, and tagIDs the ids of their tags.
+	astIDs map[*node2]string
+	tagIDs map[*node2]map[string]string
+}
+
+func (t tree2) cucumberMessages() string {
+	m := &cucumberMessages{
+		astIDs: map[*node2]string{},
+		tagIDs: map[*node2]map[string]string{},
+	}
+
+	m.emit("meta", object{
+		"protocolVersion": "24.0.0",
+		"implementation":  object{"name": "gospec"},
+		"runtime":         object{"name": "go", "version": runtime.Version()},
+		"os":              object{"name": runtime.GOOS},
+		"cpu":             object{"name": runtime.GOARCH},
+	})
+
+	type testCase struct {
+		scenario cucumberScenario
+		pickle   object
+	}
+
+	var cases []testCase
+
+	for _, f := range t {
+		m.emit("gherkinDocument", object{
+			"uri":      relativePath(f.step.file),
+			"feature":  m.feature(f),
+			"comments": []any{},
+		})
+
+		for _, sc := range collectScenarios(f) {
+			pickle := m.pickle(sc)
+			m.emit("pickle", pickle)
+			cases = append(cases, testCase{scenario: sc, pickle: pickle})
+		}
+	}
+
+	var (
+		started  time.Time
+		finished time.Time
+		success  = true
+	)
+	for _, c := range cases {
+		s := c.scenario.scenario.step
+		if s.startedAt.IsZero() {
+			continue
+		}
+		if started.IsZero() || s.startedAt.Before(started) {
+			started = s.startedAt
+		}
+		if end := s.startedAt.Add(s.timeSpent); end.After(finished) {
+			finished = end
+		}
+	}
+
+	m.emit("testRunStarted", object{"timestamp": timestamp(started)})
+
+	for _, c := range cases {
+		s := c.scenario.scenario.step
+		if s.startedAt.IsZero() {
+			// the scenario did not run, e.g. due to an invalid suite
+			continue
+		}
+
+		steps := c.scenario.steps()
+		pickleSteps := c.pickle["steps"].([]object)
+
+		testSteps := make([]object, 0, len(steps))
+		for i := range steps {
+			testSteps = append(testSteps, object{
+				"id":                      m.id(),
+				"pickleStepId":            pickleSteps[i]["id"],
+				"stepDefinitionIds":       []string{},
+				"stepMatchArgumentsLists": []any{},
+			})
+		}
+
+		testCaseID := m.id()
+		m.emit("testCase", object{"id": testCaseID, "pickleId": c.pickle["id"], "testSteps": testSteps})
+
+		startedID := m.id()
+		m.emit("testCaseStarted", object{"id": startedID, "testCaseId": testCaseID, "attempt": 0, "timestamp": timestamp(s.startedAt)})
+
+		at := s.startedAt
+		for i, n := range steps {
+			r := c.scenario.result(n)
+			if !r.startedAt.IsZero() {
+				at = r.startedAt
+			}
+
+			m.emit("testStepStarted", object{"testCaseStartedId": startedID, "testStepId": testSteps[i]["id"], "timestamp": timestamp(at)})
+
+			for _, a := range c.scenario.attachments(n) {
+				m.emit("attachment", object{
+					"testCaseStartedId": startedID,
+					"testStepId":        testSteps[i]["id"],
+					"body":              a.base64(),
+					"contentEncoding":   "BASE64",
+					"mediaType":         a.mimeType,
+					"fileName":          a.name,
+				})
+			}
+
+			if r.status == statusFailed {
+				success = false
+			}
+			at = at.Add(r.timeSpent)
+
+			m.emit("testStepFinished", object{
+				"testCaseStartedId": startedID,
+				"testStepId":        testSteps[i]["id"],
+				"testStepResult":    object{"status": strings.ToUpper(r.status), "duration": duration(r.timeSpent)},
+				"timestamp":         timestamp(at),
+			})
+		}
+
+		m.emit("testCaseFinished", object{"testCaseStartedId": startedID, "timestamp": timestamp(s.startedAt.Add(s.timeSpent)), "willBeRetried": false})
+	}
+
+	m.emit("testRunFinished", object{"success": success, "timestamp": timestamp(finished)})
+
+	return m.sb.String()
+}
+
+func (m *cucumberMessages) emit(kind string, message object) {
+	m.sb.WriteString(encodeJSON(object{kind: message}, ""))
+}
+
+func (m *cucumberMessages) id() string {
+	m.nextID++
+	return strconv.Itoa(m.nextID)
+}
+
+func timestamp(t time.Time) object {
+	if t.IsZero() {
+		return object{"seconds": 0, "nanos": 0}
+	}
+	return object{"seconds": t.Unix(), "nanos": t.Nanosecond()}
+}
+
+func duration(d time.Duration) object {
+	return object{"seconds": int64(d / time.Second), "nanos": int64(d % time.Second)}
+}
+
+func location(n *node2) object {
+	return object{"line": n.step.lineNo}
+}
+
+func (m *cucumberMessages) tags(n *node2) []object {
+	tags := make([]object, 0, len(n.step.tags))
+	m.tagIDs[n] = map[string]string{}
+	for _, tag := range n.step.tags {
+		id := m.id()
+		m.tagIDs[n][tag] = id
+		tags = append(tags, object{"id": id, "location": location(n), "name": tag})
+	}
+	return tags
+}
+
+func (m *cucumberMessages) feature(f *node2) object {
+	return object{
+		"location":    location(f),
+		"tags":        m.tags(f),
+		"language":    "en",
+		"keyword":     f.step.kind.keyword(),
+		"name":        f.step.title,
+		"description": f.step.description,
+		"children":    m.children(f),
+	}
+}
+
+func (m *cucumberMessages) children(parent *node2) []object {
+	children := []object{}
+	for _, c := range parent.children {
+		switch c.step.kind { //nolint:exhaustive
+		case isBackground:
+			children = append(children, object{"background": m.block(c)})
+		case isScenario, isScenarioOutline:
+			children = append(children, object{"scenario": m.block(c)})
+		case isRule:
+			rule := m.block(c)
+			delete(rule, "steps")
+			rule["children"] = m.children(c)
+			children = append(children, object{"rule": rule})
+		}
+	}
+	return children
+}
+
+// block returns the Gherkin document node of a background, a scenario, an outline or a rule.
+func (m *cucumberMessages) block(n *node2) object {
+	id := m.id()
+	m.astIDs[n] = id
+
+	steps := []object{}
+	for _, s := range stepNodes(n) {
+		steps = append(steps, m.step(s))
+	}
+
+	block := object{
+		"id":          id,
+		"location":    location(n),
+		"keyword":     n.step.kind.keyword(),
+		"name":        n.step.title,
+		"description": n.step.description,
+		"steps":       steps,
+	}
+
+	if n.step.kind != isBackground {
+		block["tags"] = m.tags(n)
+	}
+
+	if n.step.kind == isScenarioOutline || n.step.kind == isScenario {
+		block["examples"] = []object{}
+	}
+
+	for _, c := range n.children {
+		if c.step.kind != isExamples {
+			continue
+		}
+		rows := make([]object, 0, len(c.step.rows))
+		for i, r := range c.step.rows {
+			id := m.id()
+			if i > 0 {
+				m.astIDs[c.children[i-1]] = id
+			}
+			cells := make([]object, 0, len(r))
+			for _, cell := range r {
+				cells = append(cells, object{"location": location(n), "value": cell})
+			}
+			rows = append(rows, object{"id": id, "location": location(n), "cells": cells})
+		}
+		block["examples"] = []object{{
+			"id":          m.id(),
+			"location":    location(n),
+			"tags":        []object{},
+			"keyword":     c.step.kind.keyword(),
+			"name":        "",
+			"description": "",
+			"tableHeader": rows[0],
+			"tableBody":   rows[1:],
+		}}
+	}
+
+	return block
+}
+
+func (m *cucumberMessages) step(n *node2) object {
+	id := m.id()
+	m.astIDs[n] = id
+
+	keywordType := map[featureStepKind]string{isGiven: "Context", isWhen: "Action", isThen: "Outcome"}[n.step.kind]
+	if n.step.conjunction != "" {
+		keywordType = "Conjunction"
+	}
+
+	step := object{
+		"id":          id,
+		"location":    location(n),
+		"keyword":     n.step.keyword() + " ",
+		"keywordType": keywordType,
+		"text":        n.step.title,
+	}
+
+	rows, doc := stepArgument(n)
+	if rows != nil {
+		tableRows := make([]object, 0, len(rows))
+		for _, r := range rows {
+			cells := make([]object, 0, len(r))
+			for _, cell := range r {
+				cells = append(cells, object{"location": location(n), "value": cell})
+			}
+			tableRows = append(tableRows, object{"id": m.id(), "location": location(n), "cells": cells})
+		}
+		step["dataTable"] = object{"location": location(n), "rows": tableRows}
+	}
+	if doc != nil {
+		step["docString"] = object{"location": location(n), "content": doc.Content, "mediaType": doc.ContentType, "delimiter": `"""`}
+	}
+
+	return step
+}
+
+func (m *cucumberMessages) pickle(sc cucumberScenario) object {
+	astNodeIDs := []string{m.astIDs[sc.scenario]}
+	// the steps of an outline row are matched to the steps of the outline by their position
+	var outlineSteps []*node2
+	if sc.outline != nil {
+		astNodeIDs = []string{m.astIDs[sc.outline], m.astIDs[sc.scenario]}
+		outlineSteps = stepNodes(sc.outline)
+	}
+
+	var backgroundSteps int
+	for _, b := range sc.backgrounds {
+		backgroundSteps += len(stepNodes(b))
+	}
+
+	steps := []object{}
+	for i, n := range sc.steps() {
+		ids := []string{m.astIDs[n]}
+		if j := i - backgroundSteps; sc.outline != nil && j >= 0 && j < len(outlineSteps) {
+			ids = []string{m.astIDs[outlineSteps[j]], m.astIDs[sc.scenario]}
+		}
+
+		step := object{
+			"id":         m.id(),
+			"text":       n.step.title,
+			"type":       map[featureStepKind]string{isGiven: "Context", isWhen: "Action", isThen: "Outcome"}[n.step.kind],
+			"astNodeIds": ids,
+		}
+
+		rows, doc := stepArgument(n)
+		if rows != nil {
+			tableRows := make([]object, 0, len(rows))
+			for _, r := range rows {
+				cells := make([]object, 0, len(r))
+				for _, cell := range r {
+					cells = append(cells, object{"value": cell})
+				}
+				tableRows = append(tableRows, object{"cells": cells})
+			}
+			step["argument"] = object{"dataTable": object{"rows": tableRows}}
+		}
+		if doc != nil {
+			step["argument"] = object{"docString": object{"content": doc.Content, "mediaType": doc.ContentType}}
+		}
+
+		steps = append(steps, step)
+	}
+
+	tags := []object{}
+	for _, tag := range sc.tags() {
+		for _, n := range []*node2{sc.feature, sc.rule, sc.outline, sc.scenario} {
+			if id, ok := m.tagIDs[n][tag]; ok {
+				tags = append(tags, object{"name": tag, "astNodeId": id})
+				break
+			}
+		}
+	}
+
+	return object{
+		"id":         m.id(),
+		"uri":        relativePath(sc.feature.step.file),
+		"name":       sc.scenario.step.title,
+		"language":   "en",
+		"astNodeIds": astNodeIDs,
+		"tags":       tags,
+		"steps":      steps,
+	}
+}
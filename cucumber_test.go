@@ -0,0 +1,392 @@
+package gospec
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+// runCucumberFeature runs a feature and returns its nodes, with the timings fixed so that
+// the outputs can be compared.
+func runCucumberFeature(t *testing.T) tree2 {
+	var (
+		nodes tree2
+		tm    = &mock{t: t}
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, background, scenario, given, when, then, _ := s.With(Output(&strings.Builder{})).API()
+			_, _, thenDocString := s.DocStringAPI()
+			scenarioOutline := s.OutlineAPI()
+
+			feature("Cart", func() {
+				background(func() {
+					given("an empty cart", func(t *T) {})
+				})
+
+				scenario("adding an item", func() {
+					when("an item is added", func(t *T) {})
+					thenDocString("the cart holds", DocString{Content: "1 item"}, func(t *T, doc DocString) {})
+				}, "@smoke")
+
+				scenarioOutline("adding <count> items", [][]string{
+					{"count"},
+					{"2"},
+				}, func(row map[string]string) {
+					when("<count> items are added", func(t *T) {})
+					then("the cart is not empty", func(t *T) {})
+				})
+			}, "@cart")
+
+			nodes = s.nodes
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+
+	startedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var fix func(n *node2)
+	fix = func(n *node2) {
+		n.step.timeSpent = time.Millisecond
+		if !n.step.startedAt.IsZero() {
+			n.step.startedAt = startedAt
+		}
+		for _, c := range n.children {
+			fix(c)
+		}
+	}
+	for _, n := range nodes {
+		fix(n)
+		// the steps of each scenario run one after another
+		for _, sc := range collectScenarios(n) {
+			at := sc.scenario.step.startedAt
+			for _, step := range sc.steps() {
+				if r := sc.scenario.step.runOf(step.step); r != nil && !r.startedAt.IsZero() {
+					r.startedAt, r.timeSpent = at, time.Millisecond
+					at = at.Add(time.Millisecond)
+				}
+			}
+		}
+	}
+
+	return nodes
+}
+
+func TestCucumberJSONOutput(t *testing.T) {
+	nodes := runCucumberFeature(t)
+
+	assert.Equal(t, `[
+  {
+    "uri": "cucumber_test.go",
+    "id": "cart",
+    "keyword": "Feature",
+    "name": "Cart",
+    "description": "",
+    "line": 27,
+    "tags": [
+      {
+        "name": "@cart",
+        "line": 27
+      }
+    ],
+    "elements": [
+      {
+        "keyword": "Background",
+        "type": "background",
+        "name": "",
+        "description": "",
+        "line": 28,
+        "steps": [
+          {
+            "keyword": "Given ",
+            "name": "an empty cart",
+            "line": 29,
+            "match": {
+              "location": "cucumber_test.go:29"
+            },
+            "result": {
+              "status": "passed",
+              "duration": 1000000
+            }
+          }
+        ]
+      },
+      {
+        "id": "cart;adding-an-item",
+        "keyword": "Scenario",
+        "type": "scenario",
+        "name": "adding an item",
+        "description": "",
+        "line": 32,
+        "tags": [
+          {
+            "name": "@cart",
+            "line": 32
+          },
+          {
+            "name": "@smoke",
+            "line": 32
+          }
+        ],
+        "steps": [
+          {
+            "keyword": "When ",
+            "name": "an item is added",
+            "line": 33,
+            "match": {
+              "location": "cucumber_test.go:33"
+            },
+            "result": {
+              "status": "passed",
+              "duration": 1000000
+            }
+          },
+          {
+            "keyword": "Then ",
+            "name": "the cart holds",
+            "line": 34,
+            "doc_string": {
+              "value": "1 item"
+            },
+            "match": {
+              "location": "cucumber_test.go:34"
+            },
+            "result": {
+              "status": "passed",
+              "duration": 1000000
+            }
+          }
+        ]
+      },
+      {
+        "keyword": "Background",
+        "type": "background",
+        "name": "",
+        "description": "",
+        "line": 28,
+        "steps": [
+          {
+            "keyword": "Given ",
+            "name": "an empty cart",
+            "line": 29,
+            "match": {
+              "location": "cucumber_test.go:29"
+            },
+            "result": {
+              "status": "passed",
+              "duration": 1000000
+            }
+          }
+        ]
+      },
+      {
+        "id": "cart;adding-<count>-items;;2",
+        "keyword": "Scenario Outline",
+        "type": "scenario",
+        "name": "adding 2 items",
+        "description": "",
+        "line": 37,
+        "tags": [
+          {
+            "name": "@cart",
+            "line": 37
+          }
+        ],
+        "steps": [
+          {
+            "keyword": "When ",
+            "name": "2 items are added",
+            "line": 41,
+            "match": {
+              "location": "cucumber_test.go:41"
+            },
+            "result": {
+              "status": "passed",
+              "duration": 1000000
+            }
+          },
+          {
+            "keyword": "Then ",
+            "name": "the cart is not empty",
+            "line": 42,
+            "match": {
+              "location": "cucumber_test.go:42"
+            },
+            "result": {
+              "status": "passed",
+              "duration": 1000000
+            }
+          }
+        ]
+      }
+    ]
+  }
+]
+`, nodes.cucumberJSON())
+}
+
+func TestCucumberMessagesOutput(t *testing.T) {
+	nodes := runCucumberFeature(t)
+
+	var (
+		kinds    []string
+		pickles  []string
+		statuses []string
+		success  any
+	)
+	for _, line := range strings.Split(strings.TrimSuffix(nodes.cucumberMessages(), "\n"), "\n") {
+		var envelope map[string]map[string]any
+		if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+			t.Fatalf("invalid message %q: %s", line, err)
+		}
+		for kind, message := range envelope {
+			kinds = append(kinds, kind)
+			switch kind {
+			case "pickle":
+				pickles = append(pickles, message["name"].(string))
+			case "testStepFinished":
+				result := message["testStepResult"].(map[string]any)
+				statuses = append(statuses, result["status"].(string))
+			case "testRunFinished":
+				success = message["success"]
+			}
+		}
+	}
+
+	assert.Equal(t, []string{
+		"meta",
+		"gherkinDocument",
+		"pickle",
+		"pickle",
+		"testRunStarted",
+		"testCase",
+		"testCaseStarted",
+		"testStepStarted",
+		"testStepFinished",
+		"testStepStarted",
+		"testStepFinished",
+		"testStepStarted",
+		"testStepFinished",
+		"testCaseFinished",
+		"testCase",
+		"testCaseStarted",
+		"testStepStarted",
+		"testStepFinished",
+		"testStepStarted",
+		"testStepFinished",
+		"testStepStarted",
+		"testStepFinished",
+		"testCaseFinished",
+		"testRunFinished",
+	}, kinds)
+	assert.Equal(t, []string{"adding an item", "adding 2 items"}, pickles)
+	assert.Equal(t, []string{"PASSED", "PASSED", "PASSED", "PASSED", "PASSED", "PASSED"}, statuses)
+	assert.Equal(t, true, success)
+}
+
+func TestCucumberOutputsReportTheBackgroundStepsPerScenario(t *testing.T) {
+	var (
+		nodes     tree2
+		tm        = &mock{t: t}
+		scenarios int
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, background, scenario, given, when, _, _ := s.With(Output(&strings.Builder{})).API()
+
+			feature("Cart", func() {
+				background(func() {
+					given("an empty cart", func(t *T) {
+						scenarios++
+						Attach(t, "cart.txt", "text/plain", []byte(strings.Repeat("x", scenarios)))
+						if scenarios == 1 {
+							t.SkipNow()
+						}
+					})
+				})
+				scenario("adding an item", func() {
+					when("an item is added", func(t *T) {})
+				})
+				scenario("removing an item", func() {
+					when("an item is removed", func(t *T) {})
+				})
+			})
+
+			nodes = s.nodes
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+
+	var features []cucumberJSONFeature
+	if err := json.Unmarshal([]byte(nodes.cucumberJSON()), &features); err != nil {
+		t.Fatal(err)
+	}
+
+	var results []string
+	for _, e := range features[0].Elements {
+		for _, s := range e.Steps {
+			result := e.Type + " " + s.Name + " " + s.Result.Status
+			for _, em := range s.Embeddings {
+				result += " " + em.Data
+			}
+			results = append(results, result)
+		}
+	}
+
+	assert.Equal(t, []string{
+		"background an empty cart skipped eA==",
+		"scenario an item is added skipped",
+		"background an empty cart passed eHg=",
+		"scenario an item is removed passed",
+	}, results)
+
+	var statuses []string
+	for _, line := range strings.Split(strings.TrimSuffix(nodes.cucumberMessages(), "\n"), "\n") {
+		var envelope map[string]map[string]any
+		if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+			t.Fatalf("invalid message %q: %s", line, err)
+		}
+		if message, ok := envelope["testStepFinished"]; ok {
+			statuses = append(statuses, message["testStepResult"].(map[string]any)["status"].(string))
+		}
+	}
+
+	assert.Equal(t, []string{"SKIPPED", "SKIPPED", "PASSED", "PASSED"}, statuses)
+}
+
+func TestCucumberOutputsAttachTheCapturedOutputToTheFailedStep(t *testing.T) {
+	nodes := runCucumberFeature(t)
+
+	sc := collectScenarios(nodes[0])[0]
+	sc.scenario.step.output = "payment declined"
+	sc.scenario.step.runOf(sc.steps()[1].step).status = statusFailed
+
+	assert.Equal(t, true, strings.Contains(nodes.cucumberJSON(), strings.Join([]string{
+		`            "name": "an item is added",`,
+		`            "line": 33,`,
+		`            "match": {`,
+		`              "location": "cucumber_test.go:33"`,
+		`            },`,
+		`            "result": {`,
+		`              "status": "failed",`,
+		`              "duration": 1000000`,
+		`            },`,
+		`            "embeddings": [`,
+		`              {`,
+		`                "mime_type": "text/plain",`,
+		`                "data": "cGF5bWVudCBkZWNsaW5lZA==",`,
+		`                "name": "output"`,
+		`              }`,
+		`            ]`,
+	}, "\n")))
+	assert.Equal(t, 1, strings.Count(nodes.cucumberMessages(),
+		`"body":"cGF5bWVudCBkZWNsaW5lZA==","contentEncoding":"BASE64","fileName":"output","mediaType":"text/plain"`))
+}
@@ -0,0 +1,293 @@
+package gospec
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// DataTable is a data table argument of a step. It's passed to the steps defined via the
+// [FeatureSuite.DataTableAPI] and [FeatureSuite.ParallelDataTableAPI] functions, and can be
+// decoded into a slice of structs via [DataTable.Decode].
+type DataTable struct {
+	rows [][]string
+	at   sourceLocation
+}
+
+// Rows returns the rows of the table, whereby the first row is the header.
+func (dt DataTable) Rows() [][]string {
+	return dt.rows
+}
+
+// GivenTable is the same as [Given] but the step accepts a data table, either as a string
+// with pipe-delimited rows or as a [][]string, whereby the first row is the header.
+//
+// Example:
+//
+//	given("the following products", `
+//		| Name       | Price |
+//		| Gopher toy | 14.99 |
+//	`, func(t *testing.T, table gospec.DataTable) {
+//		var products []Product
+//		table.Decode(t, &products)
+//	})
+type GivenTable func(title string, table any, cb func(*testing.T, DataTable))
+
+// WhenTable is the same as [When] but the step accepts a data table. See [GivenTable].
+type WhenTable func(title string, table any, cb func(*testing.T, DataTable))
+
+// ThenTable is the same as [Then] but the step accepts a data table. See [GivenTable].
+type ThenTable func(title string, table any, cb func(*testing.T, DataTable))
+
+// ParallelGivenTable is the same as [GivenTable] but is used in parallel tests.
+type ParallelGivenTable func(title string, table any, cb func(*testing.T, *World, DataTable))
+
+// ParallelWhenTable is the same as [WhenTable] but is used in parallel tests.
+type ParallelWhenTable func(title string, table any, cb func(*testing.T, *World, DataTable))
+
+// ParallelThenTable is the same as [ThenTable] but is used in parallel tests.
+type ParallelThenTable func(title string, table any, cb func(*testing.T, *World, DataTable))
+
+// DataTableAPI returns the given/when/then functions for steps which accept a data table.
+// The table gets rendered under the step, as it would with the [Table] function.
+func (fs *FeatureSuite) DataTableAPI() (GivenTable, WhenTable, ThenTable) {
+	return func(title string, table any, cb func(*testing.T, DataTable)) {
+			fs.t.Helper()
+			fs.addTableStep(callerLocation(2), isGiven, title, table, cb, nil)
+		}, func(title string, table any, cb func(*testing.T, DataTable)) {
+			fs.t.Helper()
+			fs.addTableStep(callerLocation(2), isWhen, title, table, cb, nil)
+		}, func(title string, table any, cb func(*testing.T, DataTable)) {
+			fs.t.Helper()
+			fs.addTableStep(callerLocation(2), isThen, title, table, cb, nil)
+		}
+}
+
+// ParallelDataTableAPI is the same as [FeatureSuite.DataTableAPI] but is used in parallel tests.
+func (fs *FeatureSuite) ParallelDataTableAPI() (ParallelGivenTable, ParallelWhenTable, ParallelThenTable) {
+	return func(title string, table any, cb func(*testing.T, *World, DataTable)) {
+			fs.t.Helper()
+			fs.addTableStep(callerLocation(2), isGiven, title, table, nil, cb)
+		}, func(title string, table any, cb func(*testing.T, *World, DataTable)) {
+			fs.t.Helper()
+			fs.addTableStep(callerLocation(2), isWhen, title, table, nil, cb)
+		}, func(title string, table any, cb func(*testing.T, *World, DataTable)) {
+			fs.t.Helper()
+			fs.addTableStep(callerLocation(2), isThen, title, table, nil, cb)
+		}
+}
+
+func (fs *FeatureSuite) addTableStep(
+	at sourceLocation,
+	kind featureStepKind,
+	title string,
+	table any,
+	cb func(*testing.T, DataTable),
+	parallelCb func(*testing.T, *World, DataTable),
+) {
+	fs.t.Helper()
+
+	rows, err := parseDataTable(table)
+	if err != nil {
+		fs.t.Errorf("%s:%d: %s", strings.TrimPrefix(at.file, basePath), at.lineNo, err)
+		return
+	}
+
+	raw := rows
+	rows = make([][]string, 0, len(raw))
+	for _, row := range raw {
+		cells := make([]string, 0, len(row))
+		for _, cell := range row {
+			cells = append(cells, fs.interpolate(cell))
+		}
+		rows = append(rows, cells)
+	}
+
+	dt := DataTable{rows: rows, at: at}
+
+	var s *featureStep
+	if cb != nil {
+		s = fs.addStep(at, kind, title, func(t *testing.T) {
+			t.Helper()
+			cb(t, dt)
+		}, nil)
+	} else {
+		s = fs.addStep(at, kind, title, nil, func(t *testing.T, w *World) {
+			t.Helper()
+			parallelCb(t, w, dt)
+		})
+	}
+
+	s.n.children = append(s.n.children, &node2{
+		step: &featureStep{kind: isTable, rows: rows},
+	})
+	fs.addOutlineArgument(&node2{
+		step: &featureStep{kind: isTable, rows: raw},
+	})
+}
+
+// parseDataTable returns a copy of the rows of a data table, given either as
+// pipe-delimited rows or as a [][]string.
+func parseDataTable(table any) ([][]string, error) {
+	var rows [][]string
+
+	switch tbl := table.(type) {
+	case string:
+		for _, line := range strings.Split(tbl, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			if !strings.HasPrefix(line, "|") || !strings.HasSuffix(line, "|") || len(line) == 1 {
+				return nil, fmt.Errorf("invalid data table row %q, expected it to start and end with `|`", line)
+			}
+			rows = append(rows, parseTableRow(line))
+		}
+	case [][]string:
+		for _, row := range tbl {
+			rows = append(rows, append([]string{}, row...))
+		}
+	default:
+		return nil, fmt.Errorf("expected the data table to be a string or [][]string but was of type: %v", reflect.TypeOf(table))
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("expected the data table to have at least a header row")
+	}
+
+	for _, row := range rows[1:] {
+		if len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("inconsistent data table row, expected %d cells but got %d", len(rows[0]), len(row))
+		}
+	}
+
+	return rows, nil
+}
+
+// Decode decodes the rows of the table into a slice of structs, passed by pointer. The
+// header cells are matched against the `gospec:"name"` tags of the struct fields, or the
+// field names when there is no such tag. The values get converted to the field types
+// and any errors get reported at the location of the step.
+func (dt DataTable) Decode(t *testing.T, out any) {
+	t.Helper()
+	if err := dt.decode(out); err != nil {
+		t.Errorf("%s:%d: %s", strings.TrimPrefix(dt.at.file, basePath), dt.at.lineNo, err)
+	}
+}
+
+func (dt DataTable) decode(out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected a pointer to a slice but got: %v", reflect.TypeOf(out))
+	}
+
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("expected a slice of structs but got: %v", slice.Type())
+	}
+
+	fields := make([]int, 0, len(dt.rows[0]))
+	for _, header := range dt.rows[0] {
+		index, ok := fieldByColumn(structType, header)
+		if !ok {
+			return fmt.Errorf("no field in %v matches the column %q", structType, header)
+		}
+		fields = append(fields, index)
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, len(dt.rows)-1)
+	for i, row := range dt.rows[1:] {
+		item := reflect.New(structType).Elem()
+		for j, cell := range row {
+			if err := setField(item.Field(fields[j]), cell); err != nil {
+				return fmt.Errorf("row %d, column %q: %w", i+1, dt.rows[0][j], err)
+			}
+		}
+		if elemType.Kind() == reflect.Pointer {
+			item = item.Addr()
+		}
+		result = reflect.Append(result, item)
+	}
+
+	slice.Set(result)
+
+	return nil
+}
+
+// fieldByColumn returns the index of the exported struct field matching the column name,
+// either by its `gospec` tag or by its name.
+func fieldByColumn(typ reflect.Type, column string) (int, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if tag, ok := f.Tag.Lookup("gospec"); ok {
+			if tag == column {
+				return i, true
+			}
+			continue
+		}
+		if strings.EqualFold(f.Name, column) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+var ( //nolint:gochecknoglobals
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func setField(field reflect.Value, value string) error {
+	if reflect.PointerTo(field.Type()).Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch field.Type() {
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("can not convert %q to %v", value, field.Type())
+		}
+		field.SetInt(int64(d))
+		return nil
+	case timeType:
+		tm, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("can not convert %q to %v", value, field.Type())
+		}
+		field.Set(reflect.ValueOf(tm))
+		return nil
+	}
+
+	if field.Kind() == reflect.Pointer {
+		if value == "" {
+			return nil
+		}
+		ptr := reflect.New(field.Type().Elem())
+		if err := setField(ptr.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(ptr)
+		return nil
+	}
+
+	converted, err := convertArgument(value, field.Type())
+	if err != nil {
+		return err
+	}
+	field.Set(converted)
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package gospec
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+type tableProduct struct {
+	Name     string
+	Price    float64 `gospec:"Unit price"`
+	Quantity int
+	Delivery time.Duration
+	Gift     *bool
+}
+
+func TestDataTableSteps(t *testing.T) {
+	var (
+		out      bytes.Buffer
+		tm       = &mock{t: t}
+		products []tableProduct
+		rows     [][]string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, _, _, then, _ := s.With(Output(&out)).API()
+			givenTable, whenTable, _ := s.DataTableAPI()
+
+			feature("Cart", func() {
+				scenario("adding products", func() {
+					givenTable("the following products", `
+						| Name       | Unit price | Quantity | Delivery | Gift |
+						| Gopher toy | 14.99      | 2        | 48h      | true |
+						| Crab toy   | 9.5        | 1        | 24h      |      |
+					`, func(t *T, table DataTable) {
+						table.Decode(t, &products)
+					})
+					whenTable("the products are added", [][]string{
+						{"Name"},
+						{"Gopher toy"},
+					}, func(t *T, table DataTable) {
+						rows = table.Rows()
+					})
+					then("the cart is updated", func(t *T) {})
+				})
+			})
+		})
+	}()
+
+	gift := true
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []tableProduct{
+		{Name: "Gopher toy", Price: 14.99, Quantity: 2, Delivery: 48 * time.Hour, Gift: &gift},
+		{Name: "Crab toy", Price: 9.5, Quantity: 1, Delivery: 24 * time.Hour},
+	}, products)
+	assert.Equal(t, [][]string{{"Name"}, {"Gopher toy"}}, rows)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Cart`,
+		``,
+		`  ✔ Scenario: adding products`,
+		`    ✔ Given the following products`,
+		`      | Name       | Unit price | Quantity | Delivery | Gift |`,
+		`      | Gopher toy | 14.99      | 2        | 48h      | true |`,
+		`      | Crab toy   | 9.5        | 1        | 24h      |      |`,
+		`    ✔ When the products are added`,
+		`      | Name       |`,
+		`      | Gopher toy |`,
+		`    ✔ Then the cart is updated`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestDataTableStepsInOutlines(t *testing.T) {
+	var (
+		gherkin  bytes.Buffer
+		markdown bytes.Buffer
+		tm       = &mock{t: t}
+		names    []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, _, _, _, _, _ := s.With(Output(&gherkin, Gherkin), Output(&markdown, Markdown)).API()
+			scenarioOutline := s.OutlineAPI()
+			givenTable, _, _ := s.DataTableAPI()
+
+			feature("Cart", func() {
+				scenarioOutline("adding products", [][]string{
+					{"name"},
+					{"Gopher toy"},
+					{"Crab toy"},
+				}, func(row map[string]string) {
+					givenTable("the following products", `
+						| Name   |
+						| <name> |
+					`, func(t *T, table DataTable) {
+						names = append(names, table.Rows()[1][0])
+					})
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{"Gopher toy", "Crab toy"}, names)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Cart`,
+		``,
+		`  Scenario Outline: adding products`,
+		`    Given the following products`,
+		`      | Name   |`,
+		`      | <name> |`,
+		``,
+		`    Examples:`,
+		`      | name       |`,
+		`      | Gopher toy |`,
+		`      | Crab toy   |`,
+		``,
+		``,
+	}, "\n"), gherkin.String())
+	assert.Equal(t, strings.Join([]string{
+		`# Feature: Cart`,
+		``,
+		`## Scenario Outline: adding products`,
+		``,
+		`- **Given** the following products`,
+		``,
+		`  | Name |`,
+		`  | --- |`,
+		`  | <name> |`,
+		``,
+		``,
+		`### Examples`,
+		``,
+		`  |  | name |`,
+		`  | --- | --- |`,
+		`  | [x] | Gopher toy |`,
+		`  | [x] | Crab toy |`,
+		``,
+		``,
+	}, "\n"), markdown.String())
+}
+
+func TestDataTableDecodeErrors(t *testing.T) {
+	at := sourceLocation{file: "cart_test.go", lineNo: 12}
+
+	testCases := []struct {
+		title    string
+		rows     [][]string
+		out      any
+		expected string
+	}{
+		{
+			title:    "not a pointer to a slice",
+			rows:     [][]string{{"Name"}},
+			out:      []tableProduct{},
+			expected: "expected a pointer to a slice but got: []gospec.tableProduct",
+		},
+		{
+			title:    "unknown column",
+			rows:     [][]string{{"Color"}},
+			out:      &[]tableProduct{},
+			expected: `no field in gospec.tableProduct matches the column "Color"`,
+		},
+		{
+			title:    "invalid value",
+			rows:     [][]string{{"Name", "Quantity"}, {"Gopher toy", "2"}, {"Crab toy", "many"}},
+			out:      &[]*tableProduct{},
+			expected: `row 2, column "Quantity": can not convert "many" to int`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			err := DataTable{rows: tc.rows, at: at}.decode(tc.out)
+			assert.Equal(t, tc.expected, err.Error())
+		})
+	}
+}
+
+func TestDataTableIsValidated(t *testing.T) {
+	tm := &mock{t: t}
+
+	WithFeatureSuite(t, func(s *FeatureSuite) {
+		s.t = tm
+		feature, _, scenario, _, _, _, _ := s.API()
+		givenTable, _, _ := s.DataTableAPI()
+
+		feature("Cart", func() {
+			scenario("adding products", func() {
+				givenTable("the following products", "| Name | Price\n", func(t *T, table DataTable) {})
+			})
+		})
+	})
+
+	assert.Equal(t, [][]any{{
+		"%s:%d: %s", "datatable_test.go", 201,
+		errors.New("invalid data table row \"| Name | Price\", expected it to start and end with `|`"),
+	}}, tm.calls)
+}
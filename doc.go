@@ -19,4 +19,5 @@
 // Having this code-first approach makes it easier to write test cases without needing to run any other tools to
 // regenerate new test cases or test steps. And with the multiple outputs support, one can still keep definitions/specs
 // in a separate file or set of files, allowing for such files to always be synced, by being re-written on each test run.
+// Alternatively, with the [Verify] option, such files are committed and a test fails when they get out of date.
 package gospec
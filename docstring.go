@@ -0,0 +1,163 @@
+package gospec
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// DocString is a multi-line text argument of a step, e.g. a JSON or YAML payload. The
+// ContentType is optional and is rendered next to the opening `"""` delimiter.
+//
+// The common indentation of the content lines gets removed, along with the leading and
+// trailing blank lines, so that the content can be defined as an indented raw string.
+type DocString struct {
+	ContentType string
+	Content     string
+}
+
+// GivenDocString is the same as [Given] but the step accepts a [DocString].
+//
+// Example:
+//
+//	given("the following request", gospec.DocString{ContentType: "json", Content: `
+//		{"name": "Gopher toy"}
+//	`}, func(t *testing.T, doc gospec.DocString) {
+//		/* use doc.Content */
+//	})
+type GivenDocString func(title string, docString DocString, cb func(*testing.T, DocString))
+
+// WhenDocString is the same as [When] but the step accepts a [DocString].
+type WhenDocString func(title string, docString DocString, cb func(*testing.T, DocString))
+
+// ThenDocString is the same as [Then] but the step accepts a [DocString].
+type ThenDocString func(title string, docString DocString, cb func(*testing.T, DocString))
+
+// ParallelGivenDocString is the same as [GivenDocString] but is used in parallel tests.
+type ParallelGivenDocString func(title string, docString DocString, cb func(*testing.T, *World, DocString))
+
+// ParallelWhenDocString is the same as [WhenDocString] but is used in parallel tests.
+type ParallelWhenDocString func(title string, docString DocString, cb func(*testing.T, *World, DocString))
+
+// ParallelThenDocString is the same as [ThenDocString] but is used in parallel tests.
+type ParallelThenDocString func(title string, docString DocString, cb func(*testing.T, *World, DocString))
+
+// DocStringAPI returns the given/when/then functions for steps which accept a doc string.
+// The doc string gets rendered under the step, between `"""` delimiters.
+func (fs *FeatureSuite) DocStringAPI() (GivenDocString, WhenDocString, ThenDocString) {
+	return func(title string, docString DocString, cb func(*testing.T, DocString)) {
+			fs.t.Helper()
+			fs.addDocStringStep(callerLocation(2), isGiven, title, docString, cb, nil)
+		}, func(title string, docString DocString, cb func(*testing.T, DocString)) {
+			fs.t.Helper()
+			fs.addDocStringStep(callerLocation(2), isWhen, title, docString, cb, nil)
+		}, func(title string, docString DocString, cb func(*testing.T, DocString)) {
+			fs.t.Helper()
+			fs.addDocStringStep(callerLocation(2), isThen, title, docString, cb, nil)
+		}
+}
+
+// ParallelDocStringAPI is the same as [FeatureSuite.DocStringAPI] but is used in parallel tests.
+func (fs *FeatureSuite) ParallelDocStringAPI() (ParallelGivenDocString, ParallelWhenDocString, ParallelThenDocString) {
+	return func(title string, docString DocString, cb func(*testing.T, *World, DocString)) {
+			fs.t.Helper()
+			fs.addDocStringStep(callerLocation(2), isGiven, title, docString, nil, cb)
+		}, func(title string, docString DocString, cb func(*testing.T, *World, DocString)) {
+			fs.t.Helper()
+			fs.addDocStringStep(callerLocation(2), isWhen, title, docString, nil, cb)
+		}, func(title string, docString DocString, cb func(*testing.T, *World, DocString)) {
+			fs.t.Helper()
+			fs.addDocStringStep(callerLocation(2), isThen, title, docString, nil, cb)
+		}
+}
+
+func (fs *FeatureSuite) addDocStringStep(
+	at sourceLocation,
+	kind featureStepKind,
+	title string,
+	docString DocString,
+	cb func(*testing.T, DocString),
+	parallelCb func(*testing.T, *World, DocString),
+) {
+	fs.t.Helper()
+
+	raw := DocString{
+		ContentType: docString.ContentType,
+		Content:     trimDocString(docString.Content),
+	}
+	doc := DocString{
+		ContentType: raw.ContentType,
+		Content:     fs.interpolate(raw.Content),
+	}
+
+	var s *featureStep
+	if cb != nil {
+		s = fs.addStep(at, kind, title, func(t *testing.T) {
+			t.Helper()
+			cb(t, doc)
+		}, nil)
+	} else {
+		s = fs.addStep(at, kind, title, nil, func(t *testing.T, w *World) {
+			t.Helper()
+			parallelCb(t, w, doc)
+		})
+	}
+
+	s.n.children = append(s.n.children, &node2{
+		step: &featureStep{kind: isDocString, docString: &doc},
+	})
+	fs.addOutlineArgument(&node2{
+		step: &featureStep{kind: isDocString, docString: &raw},
+	})
+}
+
+// trimDocString removes the leading and trailing blank lines of the content,
+// along with the indentation which is common to all of its lines.
+func trimDocString(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			lines[i] = l[indent:]
+		} else if strings.TrimSpace(l) == "" {
+			lines[i] = ""
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// writeDocString writes the doc string between `"""` delimiters, with the delimiters
+// in the content escaped when the output needs to be valid Gherkin.
+func writeDocString(sb *strings.Builder, indent string, doc *DocString, escape bool) {
+	sb.WriteString(fmt.Sprintf("%s\"\"\"%s\n", indent, doc.ContentType))
+	for _, l := range strings.Split(doc.Content, "\n") {
+		if escape {
+			l = strings.ReplaceAll(l, `"""`, `\"\"\"`)
+		}
+		if l == "" {
+			sb.WriteString("\n")
+			continue
+		}
+		sb.WriteString(indent + l + "\n")
+	}
+	sb.WriteString(indent + "\"\"\"\n")
+}
@@ -0,0 +1,179 @@
+package gospec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestDocStringSteps(t *testing.T) {
+	var (
+		out     bytes.Buffer
+		gherkin bytes.Buffer
+		tm      = &mock{t: t}
+		payload DocString
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, _, _, then, _ := s.With(Output(&out), Output(&gherkin, Gherkin)).API()
+			givenDocString, _, _ := s.DocStringAPI()
+
+			feature("Orders API", func() {
+				scenario("creating an order", func() {
+					givenDocString("the following request", DocString{ContentType: "json", Content: `
+						{
+						  "name": "Gopher toy",
+						  "note": """
+						}
+					`}, func(t *T, doc DocString) {
+						payload = doc
+					})
+					then("the order is created", func(t *T) {})
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, DocString{
+		ContentType: "json",
+		Content:     "{\n  \"name\": \"Gopher toy\",\n  \"note\": \"\"\"\n}",
+	}, payload)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Orders API`,
+		``,
+		`  ✔ Scenario: creating an order`,
+		`    ✔ Given the following request`,
+		`      """json`,
+		`      {`,
+		`        "name": "Gopher toy",`,
+		`        "note": """`,
+		`      }`,
+		`      """`,
+		`    ✔ Then the order is created`,
+		``,
+		``,
+	}, "\n"), out.String())
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Orders API`,
+		``,
+		`  Scenario: creating an order`,
+		`    Given the following request`,
+		`      """json`,
+		`      {`,
+		`        "name": "Gopher toy",`,
+		`        "note": \"\"\"`,
+		`      }`,
+		`      """`,
+		`    Then the order is created`,
+		``,
+		``,
+	}, "\n"), gherkin.String())
+}
+
+func TestDocStringStepsInOutlines(t *testing.T) {
+	var (
+		gherkin bytes.Buffer
+		tm      = &mock{t: t}
+		seen    []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, _, _, _, _, _ := s.With(Output(&gherkin, Gherkin)).API()
+			scenarioOutline := s.OutlineAPI()
+			givenDocString, _, _ := s.DocStringAPI()
+
+			feature("Orders API", func() {
+				scenarioOutline("creating an order", [][]string{
+					{"name"},
+					{"Gopher toy"},
+					{"Gopher mug"},
+				}, func(row map[string]string) {
+					givenDocString("the following request", DocString{Content: "name: <name>"}, func(t *T, doc DocString) {
+						seen = append(seen, doc.Content)
+					})
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{"name: Gopher toy", "name: Gopher mug"}, seen)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Orders API`,
+		``,
+		`  Scenario Outline: creating an order`,
+		`    Given the following request`,
+		`      """`,
+		`      name: <name>`,
+		`      """`,
+		``,
+		`    Examples:`,
+		`      | name       |`,
+		`      | Gopher toy |`,
+		`      | Gopher mug |`,
+		``,
+		``,
+	}, "\n"), gherkin.String())
+}
+
+func TestParallelDocStringSteps(t *testing.T) {
+	var (
+		out  bytes.Buffer
+		tm   = &mock{t: t}
+		done = make(chan bool, 1)
+		seen = make(chan string, 1)
+	)
+
+	t.Run("run parallel tests", func(t *testing.T) {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, _, _, _ := s.With(Output(&out)).ParallelAPI(func() { close(done) })
+			_, whenDocString, _ := s.ParallelDocStringAPI()
+
+			feature("Orders API", func() {
+				scenario("creating an order", func() {
+					whenDocString("the order is sent", DocString{Content: "name: Gopher toy"}, func(t *T, w *World, doc DocString) {
+						seen <- doc.Content
+					})
+				})
+			})
+		})
+	})
+
+	t.Run("assert parallel tests run correctly", func(t *testing.T) {
+		t.Parallel()
+
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Errorf("test timed out")
+			return
+		}
+
+		select {
+		case content := <-seen:
+			assert.Equal(t, "name: Gopher toy", content)
+		default:
+			t.Errorf("expected the doc string step to be run")
+		}
+		assert.Equal(t, strings.Join([]string{
+			`Feature: Orders API`,
+			``,
+			`  ✔ Scenario: creating an order`,
+			`    ✔ When the order is sent`,
+			`      """`,
+			`      name: Gopher toy`,
+			`      """`,
+			``,
+			``,
+		}, "\n"), out.String())
+	})
+}
@@ -0,0 +1,232 @@
+package gospec
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// parameterType is a Cucumber expression parameter type, e.g. {int}, which is matched by
+// its pattern and converted to the type of the respective function argument.
+type parameterType struct {
+	name    string
+	pattern string
+	// accepts reports whether a function argument of the type can hold the values.
+	accepts func(typ reflect.Type) bool
+	convert func(s string, typ reflect.Type) (reflect.Value, error)
+}
+
+//nolint:gochecknoglobals
+var builtinParameterTypes = map[string]*parameterType{
+	"int": {
+		name:    "int",
+		pattern: `[-+]?\d+`,
+		accepts: kindOf(reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64),
+		convert: convertArgument,
+	},
+	"float": {
+		name:    "float",
+		pattern: `[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`,
+		accepts: kindOf(reflect.Float32, reflect.Float64),
+		convert: convertArgument,
+	},
+	"word": {
+		name:    "word",
+		pattern: `[^\s]+`,
+		accepts: kindOf(reflect.String),
+		convert: convertArgument,
+	},
+	"string": {
+		name:    "string",
+		pattern: `"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`,
+		accepts: kindOf(reflect.String),
+		convert: func(s string, typ reflect.Type) (reflect.Value, error) {
+			quote := s[:1]
+			s = strings.ReplaceAll(s[1:len(s)-1], `\`+quote, quote)
+			return convertArgument(s, typ)
+		},
+	},
+	"": {
+		name:    "",
+		pattern: `.*`,
+		accepts: kindOf(reflect.String, reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64),
+		convert: convertArgument,
+	},
+}
+
+func kindOf(kinds ...reflect.Kind) func(reflect.Type) bool {
+	return func(typ reflect.Type) bool {
+		for _, k := range kinds {
+			if typ.Kind() == k {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// newParameterType returns a custom parameter type, whose transformer is either a
+// func(string) T or a func(string) (T, error).
+func newParameterType(name, pattern string, transformer any) (*parameterType, error) {
+	if name == "" || strings.ContainsAny(name, "{}()/\\") {
+		return nil, fmt.Errorf("invalid parameter type name %q", name)
+	}
+	if _, ok := builtinParameterTypes[name]; ok {
+		return nil, fmt.Errorf("parameter type {%s} is already defined", name)
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return nil, fmt.Errorf("invalid pattern for parameter type {%s}: %w", name, err)
+	}
+
+	f := reflect.ValueOf(transformer)
+	if f.Kind() != reflect.Func {
+		return nil, fmt.Errorf("expected the transformer of parameter type {%s} to be a function but was of type: %v", name, reflect.TypeOf(transformer))
+	}
+
+	typ := f.Type()
+	withError := typ.NumOut() == 2 && typ.Out(1) == errorType
+	if typ.NumIn() != 1 || typ.In(0) != stringType || (typ.NumOut() != 1 && !withError) {
+		return nil, fmt.Errorf("expected the transformer of parameter type {%s} to be a func(string) T or a func(string) (T, error)", name)
+	}
+
+	return &parameterType{
+		name:    name,
+		pattern: pattern,
+		accepts: func(argType reflect.Type) bool {
+			return typ.Out(0).AssignableTo(argType)
+		},
+		convert: func(s string, _ reflect.Type) (reflect.Value, error) {
+			out := f.Call([]reflect.Value{reflect.ValueOf(s)})
+			if withError && !out[1].IsNil() {
+				return reflect.Value{}, fmt.Errorf("can not convert %q to {%s}: %w", s, name, out[1].Interface().(error))
+			}
+			return out[0], nil
+		},
+	}, nil
+}
+
+// defineParameterType adds a custom parameter type, which can be used by the Cucumber
+// expressions defined after it.
+func (r *stepRegistry) defineParameterType(name, pattern string, transformer any) error {
+	p, err := newParameterType(name, pattern, transformer)
+	if err != nil {
+		return err
+	}
+	if _, ok := r.parameterTypes[name]; ok {
+		return fmt.Errorf("parameter type {%s} is already defined", name)
+	}
+	if r.parameterTypes == nil {
+		r.parameterTypes = map[string]*parameterType{}
+	}
+	r.parameterTypes[name] = p
+	return nil
+}
+
+// compileExpression compiles a Cucumber expression into a regular expression. Besides the
+// parameters, e.g. {int}, the expression can hold optional text, e.g. "item(s)", and
+// alternative text, e.g. "is/are". The special characters are escaped by a backslash.
+// It returns the parameter types along with the indexes of their pattern groups.
+func compileExpression(expression string, custom map[string]*parameterType) (*regexp.Regexp, []*parameterType, []int, error) {
+	var (
+		sb     strings.Builder
+		word   strings.Builder
+		alts   []string
+		params []*parameterType
+		groups []int
+		group  = 1
+	)
+
+	// flush writes the text preceding a parameter, an optional text or a space, which
+	// is made of alternatives when it has a slash.
+	flush := func() {
+		alts = append(alts, word.String())
+		word.Reset()
+		if len(alts) == 1 {
+			sb.WriteString(regexp.QuoteMeta(alts[0]))
+		} else {
+			quoted := make([]string, 0, len(alts))
+			for _, a := range alts {
+				quoted = append(quoted, regexp.QuoteMeta(a))
+			}
+			sb.WriteString("(?:" + strings.Join(quoted, "|") + ")")
+		}
+		alts = nil
+	}
+
+	// until returns the text up to the closing character, with the escapes resolved.
+	runes := []rune(expression)
+	until := func(i int, closing rune) (string, int, error) {
+		var text strings.Builder
+		for i++; i < len(runes); i++ {
+			switch runes[i] {
+			case closing:
+				return text.String(), i, nil
+			case '\\':
+				if i+1 < len(runes) {
+					i++
+				}
+			}
+			text.WriteRune(runes[i])
+		}
+		return "", i, fmt.Errorf("invalid step expression %q, missing %q", expression, closing)
+	}
+
+	sb.WriteString("^")
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+		case r == '/':
+			alts = append(alts, word.String())
+			word.Reset()
+		case unicode.IsSpace(r):
+			flush()
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		case r == '(':
+			flush()
+			text, end, err := until(i, ')')
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			i = end
+			sb.WriteString("(?:" + regexp.QuoteMeta(text) + ")?")
+		case r == '{':
+			flush()
+			name, end, err := until(i, '}')
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			i = end
+			p, ok := custom[name]
+			if !ok {
+				p, ok = builtinParameterTypes[name]
+			}
+			if !ok {
+				return nil, nil, nil, fmt.Errorf("invalid step expression %q, undefined parameter type {%s}", expression, name)
+			}
+			sb.WriteString("(" + p.pattern + ")")
+			params = append(params, p)
+			groups = append(groups, group)
+			group += 1 + regexp.MustCompile(p.pattern).NumSubexp()
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid step expression %q: %w", expression, err)
+	}
+
+	return re, params, groups, nil
+}
@@ -0,0 +1,111 @@
+package gospec
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+type ballColor string
+
+func TestCompileExpression(t *testing.T) {
+	colors := map[string]*parameterType{}
+	p, err := newParameterType("color", `red|(green)|blue`, func(s string) ballColor { return ballColor(s) })
+	assert.Equal(t, nil, err)
+	colors["color"] = p
+
+	for _, tc := range []struct {
+		expression string
+		title      string
+		captures   []string
+	}{
+		{"the cart has {int} item(s)", "the cart has 1 item", []string{"1"}},
+		{"the cart has {int} item(s)", "the cart has -12 items", []string{"-12"}},
+		{"the price is/was {float}", "the price was 14.99", []string{"14.99"}},
+		{"the user {word} logs in", "the user gopher@go.dev logs in", []string{"gopher@go.dev"}},
+		{"the note {string} is saved", `the note "say \"hi\"" is saved`, []string{`"say \"hi\""`}},
+		{"a {color} ball and a {color} one", "a green ball and a red one", []string{"green", "red"}},
+		{`a \{literal\} \(text\) {}`, "a {literal} (text) with anything", []string{"with anything"}},
+	} {
+		re, _, groups, err := compileExpression(tc.expression, colors)
+		assert.Equal(t, nil, err)
+
+		m := re.FindStringSubmatch(tc.title)
+		captures := make([]string, 0, len(groups))
+		for _, g := range groups {
+			if m != nil {
+				captures = append(captures, m[g])
+			}
+		}
+		assert.Equal(t, tc.captures, captures)
+	}
+
+	_, _, _, err = compileExpression("the cart has {number} items", colors)
+	assert.Equal(t, `invalid step expression "the cart has {number} items", undefined parameter type {number}`, err.Error())
+}
+
+func TestDefinedStepsWithExpressions(t *testing.T) {
+	var (
+		out   bytes.Buffer
+		tm    = &mock{t: t}
+		calls []any
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, given, when, then, _ := s.With(Output(&out)).API()
+
+			s.ParameterType("color", `red|green|blue`, func(c string) (ballColor, error) {
+				return ballColor(c), nil
+			})
+			s.Define("the cart has {int} item(s)", func(t *T, n uint) { calls = append(calls, n) })
+			s.Define("a {color} ball costing {float}", func(t *T, c ballColor, price float64) { calls = append(calls, c, price) })
+			s.Define("the user writes {string}", func(t *T, note string) { calls = append(calls, note) })
+
+			feature("Cart", func() {
+				scenario("adding a ball", func() {
+					given.Use("the cart has 0 items")
+					when.Use("a red ball costing 2.5")
+					when.Use(`the user writes 'it\'s a gift'`)
+					then.Use("the cart has 1 item")
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []any{uint(0), ballColor("red"), 2.5, "it's a gift", uint(1)}, calls)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Cart`,
+		``,
+		`  ✔ Scenario: adding a ball`,
+		`    ✔ Given the cart has 0 items`,
+		`    ✔ When a red ball costing 2.5`,
+		`    ✔ When the user writes 'it\'s a gift'`,
+		`    ✔ Then the cart has 1 item`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestDefinedStepsAreCheckedAgainstTheParameterTypes(t *testing.T) {
+	tm := &mock{t: t}
+
+	WithFeatureSuite(t, func(s *FeatureSuite) {
+		s.t = tm
+
+		s.Define("the cart has {int} items", func(t *T, n string) {})
+		s.Define("the cart has {int} items and {word}", func(t *T, n int) {})
+		s.ParameterType("int", `\d+`, func(s string) int { return 0 })
+	})
+
+	assert.Equal(t, [][]any{
+		{"%s", errors.New(`step definition "the cart has {int} items": can not use {int} as string`)},
+		{"%s", errors.New(`step definition "the cart has {int} items and {word}" has 2 parameters but the function takes 1 arguments after the *testing.T`)},
+		{"%s", errors.New(`parameter type {int} is already defined`)},
+	}, tm.calls)
+}
@@ -89,9 +89,9 @@ func TestFeaturesContainOnlyScenariosAndBackgroundCalls(t *testing.T) {
 		``,
 		`  Background:`,
 		``,
-		`  Scenario: scenario 1`,
+		`  ✔ Scenario: scenario 1`,
 		``,
-		`  Scenario: scenario 2`,
+		`  ✔ Scenario: scenario 2`,
 		``,
 		``,
 	}, "\n"), out.String())
@@ -116,7 +116,7 @@ func TestScenariosCanNotBeNested(t *testing.T) {
 		})
 	}()
 
-	assert.Equal(t, [][]any{{"invalid position for `Scenario` function, it must be inside a `Feature` call"}}, testingMock.calls)
+	assert.Equal(t, [][]any{{"invalid position for `Scenario` function, it must be inside a `Feature` or `Rule` call"}}, testingMock.calls)
 	assert.Equal(t, []string{"Checkout/scenario 1"}, testingMock.testTitles)
 
 	assert.Equal(t, "", out.String())
@@ -162,10 +162,10 @@ func TestScenarioCanContainGivenWhenThen(t *testing.T) {
 	assert.Equal(t, strings.Join([]string{
 		`Feature: Checkout`,
 		``,
-		`  Scenario: scenario 1`,
-		`    Given given 1`,
-		`    When when 1`,
-		`    Then then 1`,
+		`  ✔ Scenario: scenario 1`,
+		`    ✔ Given given 1`,
+		`    ✔ When when 1`,
+		`    ✔ Then then 1`,
 		``,
 		``,
 	}, "\n"), out.String())
@@ -222,15 +222,15 @@ func TestMultipleScenarioWithGivenWhenThen(t *testing.T) {
 	assert.Equal(t, strings.Join([]string{
 		`Feature: Checkout`,
 		``,
-		`  Scenario: scenario 1`,
-		`    Given given 1`,
-		`    When when 1`,
-		`    Then then 1`,
+		`  ✔ Scenario: scenario 1`,
+		`    ✔ Given given 1`,
+		`    ✔ When when 1`,
+		`    ✔ Then then 1`,
 		``,
-		`  Scenario: scenario 2`,
-		`    Given given 2`,
-		`    When when 2`,
-		`    Then then 2`,
+		`  ✔ Scenario: scenario 2`,
+		`    ✔ Given given 2`,
+		`    ✔ When when 2`,
+		`    ✔ Then then 2`,
 		``,
 		``,
 	}, "\n"), out.String())
@@ -283,14 +283,14 @@ func TestScenarioWhichHasBackgroundBlock(t *testing.T) {
 		`Feature: Checkout`,
 		``,
 		`  Background:`,
-		`    Given given 0`,
-		`    When when 0`,
-		`    Then then 0`,
+		`    ✔ Given given 0`,
+		`    ✔ When when 0`,
+		`    ✔ Then then 0`,
 		``,
-		`  Scenario: scenario 1`,
-		`    Given given 1`,
-		`    When when 1`,
-		`    Then then 1`,
+		`  ✔ Scenario: scenario 1`,
+		`    ✔ Given given 1`,
+		`    ✔ When when 1`,
+		`    ✔ Then then 1`,
 		``,
 		``,
 	}, "\n"), out.String())
@@ -368,19 +368,19 @@ func TestMultipleScenariosWhichShareTheSameBackgroundBlock(t *testing.T) {
 		`Feature: Checkout`,
 		``,
 		`  Background:`,
-		`    Given given 0`,
-		`    When when 0`,
-		`    Then then 0`,
+		`    ✔ Given given 0`,
+		`    ✔ When when 0`,
+		`    ✔ Then then 0`,
 		``,
-		`  Scenario: scenario 1`,
-		`    Given given 1`,
-		`    When when 1`,
-		`    Then then 1`,
+		`  ✔ Scenario: scenario 1`,
+		`    ✔ Given given 1`,
+		`    ✔ When when 1`,
+		`    ✔ Then then 1`,
 		``,
-		`  Scenario: scenario 2`,
-		`    Given given 2`,
-		`    When when 2`,
-		`    Then then 2`,
+		`  ✔ Scenario: scenario 2`,
+		`    ✔ Given given 2`,
+		`    ✔ When when 2`,
+		`    ✔ Then then 2`,
 		``,
 		``,
 	}, "\n"), out.String())
@@ -505,36 +505,36 @@ func TestFeaturesGetExecutedInCorrectOrder(t *testing.T) {
 		`Feature: Checkout 1`,
 		``,
 		`  Background:`,
-		`    Given given 1`,
-		`    When when 1`,
-		`    Then then 1`,
+		`    ✔ Given given 1`,
+		`    ✔ When when 1`,
+		`    ✔ Then then 1`,
 		``,
-		`  Scenario: scenario 1`,
-		`    Given given 2`,
-		`    When when 2`,
-		`    Then then 2`,
+		`  ✔ Scenario: scenario 1`,
+		`    ✔ Given given 2`,
+		`    ✔ When when 2`,
+		`    ✔ Then then 2`,
 		``,
-		`  Scenario: scenario 2`,
-		`    Given given 2`,
-		`    When when 2`,
-		`    Then then 2`,
+		`  ✔ Scenario: scenario 2`,
+		`    ✔ Given given 2`,
+		`    ✔ When when 2`,
+		`    ✔ Then then 2`,
 		``,
 		`Feature: Checkout 2`,
 		``,
 		`  Background:`,
-		`    Given given 11`,
-		`    When when 11`,
-		`    Then then 11`,
+		`    ✔ Given given 11`,
+		`    ✔ When when 11`,
+		`    ✔ Then then 11`,
 		``,
-		`  Scenario: scenario 11`,
-		`    Given given 12`,
-		`    When when 12`,
-		`    Then then 12`,
+		`  ✔ Scenario: scenario 11`,
+		`    ✔ Given given 12`,
+		`    ✔ When when 12`,
+		`    ✔ Then then 12`,
 		``,
-		`  Scenario: scenario 12`,
-		`    Given given 12`,
-		`    When when 12`,
-		`    Then then 12`,
+		`  ✔ Scenario: scenario 12`,
+		`    ✔ Given given 12`,
+		`    ✔ When when 12`,
+		`    ✔ Then then 12`,
 		``,
 		``,
 	}, "\n"), out.String())
@@ -730,42 +730,42 @@ func TestFeaturesGetExecutedInParallel(t *testing.T) {
 			`Feature: Checkout 1`,
 			``,
 			`  Background:`,
-			`    Given given 1`,
+			`    ✔ Given given 1`,
 			`      | Name       | Price |`,
-			`      | Gopher toy | 1.99  |`,
-			`      | Crab toy   | 2.49  |`,
-			`    When when 1`,
-			`    Then then 1`,
+			`      | Gopher toy |  1.99 |`,
+			`      | Crab toy   |  2.49 |`,
+			`    ✔ When when 1`,
+			`    ✔ Then then 1`,
 			``,
-			`  Scenario: scenario 1`,
-			`    Given given 2`,
-			`    When when 2`,
-			`    Then then 2`,
+			`  ✔ Scenario: scenario 1`,
+			`    ✔ Given given 2`,
+			`    ✔ When when 2`,
+			`    ✔ Then then 2`,
 			``,
-			`  Scenario: scenario 2`,
-			`    Given given 2`,
-			`    When when 2`,
-			`    Then then 2`,
+			`  ✔ Scenario: scenario 2`,
+			`    ✔ Given given 2`,
+			`    ✔ When when 2`,
+			`    ✔ Then then 2`,
 			``,
 			`Feature: Checkout 2`,
 			``,
 			`  Background:`,
-			`    Given given 11`,
-			`    When when 11`,
-			`    Then then 11`,
+			`    ✔ Given given 11`,
+			`    ✔ When when 11`,
+			`    ✔ Then then 11`,
 			``,
-			`  Scenario: scenario 11`,
-			`    Given given 12`,
-			`    When when 12`,
-			`    Then then 12`,
+			`  ✔ Scenario: scenario 11`,
+			`    ✔ Given given 12`,
+			`    ✔ When when 12`,
+			`    ✔ Then then 12`,
 			``,
-			`  Scenario: scenario 12`,
-			`    Given given 12`,
+			`  ✔ Scenario: scenario 12`,
+			`    ✔ Given given 12`,
 			`      | Name       | Price |`,
 			`      | Gopher toy | 14.99 |`,
 			`      | Crab toy   | 17.49 |`,
-			`    When when 12`,
-			`    Then then 12`,
+			`    ✔ When when 12`,
+			`    ✔ Then then 12`,
 			``,
 			``,
 		}, "\n"), out.String())
@@ -826,10 +826,10 @@ func TestTableOutput(t *testing.T) {
 	assert.Equal(t, strings.Join([]string{
 		`Feature: Checkout`,
 		``,
-		`  Scenario: scenario 1`,
-		`    Given given 1`,
-		`    When when 1`,
-		`    Then then 1`,
+		`  ✔ Scenario: scenario 1`,
+		`    ✔ Given given 1`,
+		`    ✔ When when 1`,
+		`    ✔ Then then 1`,
 		`      | Name       | Price |`,
 		`      | Gopher toy | 14.99 |`,
 		`      | Crab toy   | 17.49 |`,
@@ -899,12 +899,12 @@ func TestColorfulOutput(t *testing.T) {
 		"\x1b[1m" + `Feature:` + "\x1b[0m" + ` Checkout`, //nolint:goconst
 		``,
 		`  ` + "\x1b[1m" + `Background:` + "\x1b[0m",
-		`    ` + "\x1b[0;36m" + `Given` + "\x1b[0m" + ` given 0`, //nolint:goconst
+		`    ` + "\x1b[0;32m✔\x1b[0m " + "\x1b[0;36m" + `Given` + "\x1b[0m" + ` given 0`, //nolint:goconst
 		``,
-		`  ` + "\x1b[1m" + `Scenario:` + "\x1b[0m" + ` scenario 1`,
-		`    ` + "\x1b[0;36m" + `Given` + "\x1b[0m" + ` given 1`,
-		`    ` + "\x1b[0;32m" + `When` + "\x1b[0m" + ` when 1`,
-		`    ` + "\x1b[0;33m" + `Then` + "\x1b[0m" + ` then 1`,
+		`  ` + "\x1b[0;32m✔\x1b[0m " + "\x1b[1m" + `Scenario:` + "\x1b[0m" + ` scenario 1`,
+		`    ` + "\x1b[0;32m✔\x1b[0m " + "\x1b[0;36m" + `Given` + "\x1b[0m" + ` given 1`,
+		`    ` + "\x1b[0;32m✔\x1b[0m " + "\x1b[0;32m" + `When` + "\x1b[0m" + ` when 1`,
+		`    ` + "\x1b[0;32m✔\x1b[0m " + "\x1b[0;33m" + `Then` + "\x1b[0m" + ` then 1`,
 		`      | Name       | Price |`,
 		`      | Gopher toy | 14.99 |`,
 		`      | Crab toy   | 17.49 |`,
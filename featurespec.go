@@ -1,14 +1,13 @@
 package gospec
 
 import (
-	"fmt"
 	"io"
-	"reflect"
+	"os"
 	"runtime"
-	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 type featureStepKind int
@@ -22,16 +21,28 @@ const (
 	isWhen
 	isThen
 	isTable
+	isScenarioOutline
+	isExamples
+	isDocString
+	isRule
+	// isAnd and isBut are used only when defining steps, and get resolved
+	// to the kind of the preceding step.
+	isAnd
+	isBut
 )
 
-// Feature is a helper function to define a new feature.
-type Feature func(title string, cb func())
+// Feature is a helper function to define a new feature. The optional tags, e.g. `@smoke`,
+// are inherited by all of its scenarios. See [Scenario] for how the tags are used.
+type Feature func(title string, cb func(), tags ...string)
 
 // Background is a helper function to define the background (set of preconditions) for one or more scenarios.
 type Background func(cb func())
 
-// Scenario is used to define a specific test case.
-type Scenario func(title string, cb func())
+// Scenario is used to define a specific test case. The optional tags, e.g. `@smoke`, are
+// rendered above the scenario and can be used for selecting which scenarios to run, via a
+// tag expression in the GOSPEC_TAGS environment variable, e.g. `@smoke and not @wip`.
+// The scenarios which are not selected get reported as skipped.
+type Scenario func(title string, cb func(), tags ...string)
 
 // Given is used to define a precondition for a test case.
 type Given func(title string, cb func(*testing.T))
@@ -58,7 +69,16 @@ type Then func(title string, cb func(*testing.T))
 //	| Crab toy   | 17.49 |
 //
 // whereby the variadic arguments passed after the list of items to get displayed
-// in the table, is the list of public fields on the struct.
+// in the table, is the list of public fields on the struct. A column matches the
+// `gospec:"Header"` tag of a field, or its name, and the fields of nested structs
+// are selected by their path, e.g. "Address.City". When no columns are passed, all
+// of the public fields get displayed. The columns holding numbers are right-aligned.
+//
+// Besides a slice of structs, the items can be a slice of maps, whose keys are the
+// columns, a [][]string whose first row is the header, or a single struct or map,
+// which gets displayed vertically, with a row per field. The items can be wrapped by
+// [TableItems], for passing options such as [ColumnFormat] or [MaxWidth]. The Table
+// function must be called inside a given/when/then step.
 type Table func(items any, columns ...string)
 
 // ParallelGiven is used to define a precondition for a test case. It's used in tests that are meant to be executed in parallel, via the [FeatureSuite.ParallelAPI].
@@ -70,20 +90,69 @@ type ParallelWhen func(title string, cb func(*testing.T, *World))
 // ParallelThen is used to define a set of assertions. It's used in tests that are meant to be executed in parallel, via the [FeatureSuite.ParallelAPI].
 type ParallelThen func(title string, cb func(*testing.T, *World))
 
+type sourceLocation struct {
+	file   string
+	lineNo int
+}
+
+// callerLocation returns the source location of the caller, whereby skip is the
+// number of stack frames to ascend, with 1 identifying the caller of callerLocation.
+func callerLocation(skip int) sourceLocation {
+	_, file, lineNo, _ := runtime.Caller(skip)
+	return sourceLocation{file: file, lineNo: lineNo}
+}
+
 type featureStep struct {
-	t          *testing.T
-	kind       featureStepKind
-	title      string
-	file       string
-	lineNo     int
-	failed     bool
-	failedAt   int
-	executed   bool
+	t           *testing.T
+	kind        featureStepKind
+	title       string
+	description string
+	rows        [][]string
+	// numeric marks the columns of a table built from Go values which hold numbers.
+	numeric     []bool
+	docString   *DocString
+	conjunction string
+	tags        []string
+	file        string
+	lineNo      int
+	// undefined is set for the steps which have no step definition, see [FeatureSuite.Define].
+	undefined bool
+	// status is the status of an executed given/when/then step, see [FeatureSuite.setStepStatus].
+	status string
+	// timeSpent is the time spent in the scenario, or in the step. A background step is
+	// shared by the scenarios, so it gets the time of the run its status comes from.
+	timeSpent time.Duration
+	// startedAt is the time the scenario started at.
+	startedAt time.Time
+	// attachments are the ones added via [Attach] or [World.Attach] while running the step.
+	attachments []*attachment
+	// runs are the results of the given/when/then steps run as part of the scenario, by the
+	// steps, including the shared background steps.
+	runs map[*featureStep]*featureStepRun
+	// output is the output captured while running a failed scenario, see [CaptureOutput].
+	output     string
 	parallelCb func(*testing.T, *World)
 	cb         func(*testing.T)
 	n          *node2
 }
 
+// featureStepRun is the result of a given/when/then step in one of the scenarios it's run
+// in, as the background steps are shared by the scenarios.
+type featureStepRun struct {
+	status      string
+	startedAt   time.Time
+	timeSpent   time.Duration
+	attachments []*attachment
+}
+
+// runOf returns the result of a step in the scenario, or nil when the step did not run.
+func (s *featureStep) runOf(step *featureStep) *featureStepRun {
+	if s == nil {
+		return nil
+	}
+	return s.runs[step]
+}
+
 // FeatureSuite is a test suite which is inspired by the Cucumber/Gherkin
 // style of writing tests, in terms of defining: features, scenarios and
 // given/when/then steps.
@@ -100,6 +169,9 @@ type FeatureSuite struct {
 	done            func()
 	stack           []*featureStep
 	backgroundStack []*featureStep
+	rule            *featureStep
+	hooks           hooks
+	ruleBackground  []*featureStep
 	suites          [][]*featureStep
 	inBackground    bool
 	atSuiteIndex    int
@@ -111,9 +183,20 @@ type FeatureSuite struct {
 	nodesStack      []*node2
 	wg              *sync.WaitGroup
 	invalid         bool
-	failedCount     int
 	mu              sync.Mutex
 	currentStep     *featureStep
+	steps           stepRegistry
+	libraries       []*StepLibrary
+	usedSteps       map[*stepDefinition]bool
+	undefinedSteps  []undefinedStep
+	strict          bool
+	outline         *outline
+	stepSubtests    bool
+	allureDir       string
+	capture         bool
+	// stderr receives the reports of the suite which aren't part of its outputs, i.e. the
+	// unused library steps and the undefined steps.
+	stderr io.Writer
 }
 
 // NewFeatureSuite returns a new [FeatureSuite] instance.
@@ -122,6 +205,7 @@ func newFeatureSuite(t *testing.T) *FeatureSuite {
 	fs := &FeatureSuite{
 		t:        t,
 		basePath: getBasePath(),
+		stderr:   originalStderr{},
 	}
 	return fs
 }
@@ -215,7 +299,12 @@ func WithFeatureSuite(t *testing.T, callback func(fs *FeatureSuite)) {
 
 // Feature defines a feature block, this is the top-level block and should
 // define a separate piece of functionality.
-func (fs *FeatureSuite) feature(title string, cb func()) {
+func (fs *FeatureSuite) feature(title string, cb func(), tags ...string) {
+	fs.t.Helper()
+	fs.featureAt(callerLocation(2), title, cb, tags...)
+}
+
+func (fs *FeatureSuite) featureAt(at sourceLocation, title string, cb func(), tags ...string) {
 	fs.t.Helper()
 	if fs.prevKind() != isUndefined {
 		fs.invalid = true
@@ -223,9 +312,11 @@ func (fs *FeatureSuite) feature(title string, cb func()) {
 		return
 	}
 
-	_, file, lineNo, _ := runtime.Caller(1)
-	_ = file
-	_ = lineNo
+	if err := validateTags(tags); err != nil {
+		fs.invalid = true
+		fs.t.Errorf("%s", err)
+		return
+	}
 
 	n := &node2{}
 
@@ -233,11 +324,15 @@ func (fs *FeatureSuite) feature(title string, cb func()) {
 
 	fs.currNode = n
 
+	title, description := splitTitle(title)
+
 	s := &featureStep{
-		kind:   isFeature,
-		title:  title,
-		file:   file,
-		lineNo: lineNo,
+		kind:        isFeature,
+		title:       title,
+		description: description,
+		tags:        tags,
+		file:        at.file,
+		lineNo:      at.lineNo,
 	}
 
 	n.step = s
@@ -276,6 +371,10 @@ func (fs *FeatureSuite) pushStack2(n *node2) {
 
 func (fs *FeatureSuite) pushToBackgroundStack(s *featureStep) {
 	fs.t.Helper()
+	if fs.rule != nil {
+		fs.ruleBackground = append(fs.ruleBackground, s)
+		return
+	}
 	fs.backgroundStack = append(fs.backgroundStack, s)
 }
 
@@ -362,21 +461,22 @@ func (fs *FeatureSuite) findIndexOfStep(s *featureStep) int {
 // It can contain multiple [FeatureSuite.Given] steps.
 func (fs *FeatureSuite) background(cb func()) {
 	fs.t.Helper()
-	if fs.prevKind() != isFeature {
-		fs.t.Errorf("invalid position for `Background` function, it must be inside a `Feature` call")
+	fs.backgroundAt(callerLocation(2), cb)
+}
+
+func (fs *FeatureSuite) backgroundAt(at sourceLocation, cb func()) {
+	fs.t.Helper()
+	if fs.prevKind() != isFeature && fs.prevKind() != isRule {
+		fs.t.Errorf("invalid position for `Background` function, it must be inside a `Feature` or `Rule` call")
 		return
 	}
 
-	_, file, lineNo, _ := runtime.Caller(1)
-	_ = file
-	_ = lineNo
-
 	n := &node2{}
 
 	s := &featureStep{
 		kind:   isBackground,
-		file:   file,
-		lineNo: lineNo,
+		file:   at.file,
+		lineNo: at.lineNo,
 	}
 
 	n.step = s
@@ -436,25 +536,36 @@ func (fs *FeatureSuite) findIndexOfNode(n *node2) int {
 
 // Scenario defines a scenario block. It should test a particular feature in a particular
 // scenario, provided a set of given/when/then steps.
-func (fs *FeatureSuite) scenario(title string, cb func()) {
+func (fs *FeatureSuite) scenario(title string, cb func(), tags ...string) {
+	fs.t.Helper()
+	fs.scenarioAt(callerLocation(2), title, cb, tags...)
+}
+
+func (fs *FeatureSuite) scenarioAt(at sourceLocation, title string, cb func(), tags ...string) {
 	fs.t.Helper()
-	if fs.prevKind() != isFeature && fs.prevKind() != isBackground {
+	if fs.prevKind() != isFeature && fs.prevKind() != isBackground && fs.prevKind() != isRule {
 		fs.invalid = true
-		fs.t.Errorf("invalid position for `Scenario` function, it must be inside a `Feature` call")
+		fs.t.Errorf("invalid position for `Scenario` function, it must be inside a `Feature` or `Rule` call")
 		return
 	}
 
-	_, file, lineNo, _ := runtime.Caller(1)
-	_ = file
-	_ = lineNo
+	if err := validateTags(tags); err != nil {
+		fs.invalid = true
+		fs.t.Errorf("%s", err)
+		return
+	}
 
 	n := &node2{}
 
+	title, description := splitTitle(title)
+
 	s := &featureStep{
-		kind:   isScenario,
-		title:  title,
-		lineNo: lineNo,
-		file:   file,
+		kind:        isScenario,
+		title:       title,
+		description: description,
+		tags:        tags,
+		lineNo:      at.lineNo,
+		file:        at.file,
 	}
 	fs.pushStack(s)
 
@@ -483,189 +594,83 @@ func (fs *FeatureSuite) scenario(title string, cb func()) {
 // block.
 func (fs *FeatureSuite) given(title string, cb func(*testing.T)) {
 	fs.t.Helper()
-
-	_, file, lineNo, _ := runtime.Caller(1)
-	_ = file
-	_ = lineNo
-
-	n := &node2{}
-
-	s := &featureStep{
-		kind:   isGiven,
-		title:  title,
-		lineNo: lineNo,
-		file:   file,
-	}
-
-	s.cb = func(t *testing.T) {
-		fs.t.Helper()
-		cb(t)
-		s.executed = true
-
-		if fs.t.Failed() {
-			s.failed = true
-			fs.failedCount++
-			s.failedAt = fs.failedCount
-		}
-	}
-
-	n.step = s
-	fs.currNode.children = append(fs.currNode.children, n)
-
-	s.n = n
-
-	if fs.inBackground {
-		fs.pushToBackgroundStack(s)
-	} else {
-		fs.pushStack(s)
-	}
+	fs.defineStep(isGiven, title, cb, nil)
 }
 
 func (fs *FeatureSuite) parallelGiven(title string, cb func(*testing.T, *World)) {
 	fs.t.Helper()
-
-	_, file, lineNo, _ := runtime.Caller(1)
-	_ = file
-	_ = lineNo
-
-	n := &node2{}
-
-	s := &featureStep{
-		kind:   isGiven,
-		title:  title,
-		lineNo: lineNo,
-		file:   file,
-	}
-
-	s.parallelCb = func(t *testing.T, w *World) {
-		w.t.Helper()
-
-		cb(t, w)
-		// s.executed = true
-
-		// if w.t.Failed() {
-		// 	//s.failed = true
-		// 	//fs.failedCount++
-		// 	//s.failedAt = fs.failedCount
-		// }
-	}
-
-	n.step = s
-	fs.currNode.children = append(fs.currNode.children, n)
-
-	s.n = n
-
-	if fs.inBackground {
-		fs.pushToBackgroundStack(s)
-	} else {
-		fs.pushStack(s)
-	}
+	fs.defineStep(isGiven, title, nil, cb)
 }
 
 // When defines a block which should exercise the actual test.
 func (fs *FeatureSuite) when(title string, cb func(*testing.T)) {
 	fs.t.Helper()
-
-	_, file, lineNo, _ := runtime.Caller(1)
-	_ = file
-	_ = lineNo
-
-	n := &node2{}
-
-	s := &featureStep{
-		kind:   isWhen,
-		title:  title,
-		lineNo: lineNo,
-		file:   file,
-		cb:     cb,
-	}
-
-	n.step = s
-	fs.currNode.children = append(fs.currNode.children, n)
-
-	s.n = n
-
-	if fs.inBackground {
-		fs.pushToBackgroundStack(s)
-	} else {
-		fs.pushStack(s)
-	}
+	fs.defineStep(isWhen, title, cb, nil)
 }
 
 func (fs *FeatureSuite) parallelWhen(title string, cb func(*testing.T, *World)) {
 	fs.t.Helper()
-
-	_, file, lineNo, _ := runtime.Caller(1)
-	_ = file
-	_ = lineNo
-
-	n := &node2{}
-
-	s := &featureStep{
-		kind:       isWhen,
-		title:      title,
-		lineNo:     lineNo,
-		file:       file,
-		parallelCb: cb,
-	}
-
-	n.step = s
-	fs.currNode.children = append(fs.currNode.children, n)
-
-	s.n = n
-
-	if fs.inBackground {
-		fs.pushToBackgroundStack(s)
-	} else {
-		fs.pushStack(s)
-	}
+	fs.defineStep(isWhen, title, nil, cb)
 }
 
 // Then defines a block which should hold a set of assertions.
 func (fs *FeatureSuite) then(title string, cb func(*testing.T)) {
 	fs.t.Helper()
+	fs.defineStep(isThen, title, cb, nil)
+}
 
-	_, file, lineNo, _ := runtime.Caller(1)
-	_ = file
-	_ = lineNo
-
-	n := &node2{}
-
-	s := &featureStep{
-		kind:   isThen,
-		title:  title,
-		lineNo: lineNo,
-		file:   file,
-		cb:     cb,
-	}
-
-	n.step = s
-	fs.currNode.children = append(fs.currNode.children, n)
+func (fs *FeatureSuite) parallelThen(title string, cb func(*testing.T, *World)) {
+	fs.t.Helper()
+	fs.defineStep(isThen, title, nil, cb)
+}
 
-	s.n = n
+// defineStep adds a given/when/then step with the callback, or a step implemented by a step
+// definition, when it's added via [Given.Use] and the rest. It's called by the given/when/then
+// functions, so the caller of those is the one 3 stack frames above it, or 4 when called
+// from Use.
+func (fs *FeatureSuite) defineStep(kind featureStepKind, title string, cb func(*testing.T), parallelCb func(*testing.T, *World)) {
+	fs.t.Helper()
 
-	if fs.inBackground {
-		fs.pushToBackgroundStack(s)
-	} else {
-		fs.pushStack(s)
+	switch {
+	case isUseStep(cb, parallelCb):
+		fs.importStep(callerLocation(4), kind, title, nil)
+	case cb == nil && parallelCb == nil:
+		at := callerLocation(3)
+		fs.t.Errorf("%s:%d: missing callback for the `%s` step %q, the steps implemented by a step definition are added via `%s.Use`",
+			strings.TrimPrefix(at.file, basePath), at.lineNo, kind.keyword(), title, kind.keyword())
+	default:
+		fs.addStep(callerLocation(3), kind, title, cb, parallelCb)
 	}
 }
 
-func (fs *FeatureSuite) parallelThen(title string, cb func(*testing.T, *World)) {
+// addStep adds a given/when/then step to the current scenario or background.
+func (fs *FeatureSuite) addStep(
+	at sourceLocation,
+	kind featureStepKind,
+	title string,
+	cb func(*testing.T),
+	parallelCb func(*testing.T, *World),
+) *featureStep {
 	fs.t.Helper()
 
-	_, file, lineNo, _ := runtime.Caller(1)
-	_ = file
-	_ = lineNo
+	var conjunction string
+	if kind == isAnd || kind == isBut {
+		conjunction = kind.keyword()
+		kind = fs.precedingStepKind(conjunction)
+	}
+
+	fs.addOutlineStep(at, kind, conjunction, title)
 
 	n := &node2{}
 
 	s := &featureStep{
-		kind:       isThen,
-		title:      title,
-		lineNo:     lineNo,
-		file:       file,
-		parallelCb: cb,
+		kind:        kind,
+		conjunction: conjunction,
+		title:       fs.interpolate(title),
+		lineNo:      at.lineNo,
+		file:        at.file,
+		cb:          cb,
+		parallelCb:  parallelCb,
 	}
 
 	n.step = s
@@ -678,6 +683,8 @@ func (fs *FeatureSuite) parallelThen(title string, cb func(*testing.T, *World))
 	} else {
 		fs.pushStack(s)
 	}
+
+	return s
 }
 
 func (fs *FeatureSuite) copyStack() {
@@ -686,100 +693,46 @@ func (fs *FeatureSuite) copyStack() {
 		return
 	}
 
-	suite := make([]*featureStep, 0, len(fs.stack)+len(fs.backgroundStack))
+	suite := make([]*featureStep, 0, len(fs.stack)+len(fs.backgroundStack)+len(fs.ruleBackground))
 	suite = append(suite, fs.stack[:1]...)
 	suite = append(suite, fs.backgroundStack...)
-	suite = append(suite, fs.stack[1:]...)
+	if fs.rule != nil {
+		// the rule background runs after the feature background
+		suite = append(suite, fs.stack[1])
+		suite = append(suite, fs.ruleBackground...)
+		suite = append(suite, fs.stack[2:]...)
+	} else {
+		suite = append(suite, fs.stack[1:]...)
+	}
 	fs.suites = append(fs.suites, suite)
 }
 
 // Table is a utility function to only visualize test data in a table.
-func (fs *FeatureSuite) table(items any, columns ...string) { //nolint:gocognit,cyclop
+func (fs *FeatureSuite) table(items any, columns ...string) {
 	fs.t.Helper()
 
-	// TODO: detect when using in "parallel" context, and if yes, error. Should use the `World.Table` method in such cases.
-
-	// TODO: validate table was called in valid call site
-
-	var sb strings.Builder
-	n := &node2{}
-
-	items2 := reflect.ValueOf(items)
-
-	if items2.Kind() != reflect.Slice {
-		fs.t.Errorf("expected items to be of type slice but was of type: %v", reflect.TypeOf(items))
+	if fs.parallel {
+		fs.t.Errorf("the `Table` function can not be used in parallel tests, use the `World.Table` method instead")
 		return
 	}
 
-	columnWidths := make(map[string]int, items2.Len())
-	_ = columnWidths
-
-	for _, x := range columns {
-		columnWidths[x] = len(x)
+	if fs.currentStep == nil {
+		fs.t.Errorf("invalid position for `Table` function, it must be called inside a `Given`, `When` or `Then` step")
+		return
 	}
 
-	var rows []map[string]string
-
-	for i := 0; i < items2.Len(); i++ {
-		item := items2.Index(i)
-		if item.Kind() == reflect.Struct {
-			row := map[string]string{}
-			v := reflect.Indirect(item)
-			for j := 0; j < v.NumField(); j++ {
-				name := v.Type().Field(j).Name
-				value := v.Field(j).Interface()
-				maxWidth, ok := columnWidths[name]
-				_ = maxWidth
-				if !ok {
-					continue
-				}
-				switch z := value.(type) {
-				case string:
-					if len(z) > maxWidth {
-						columnWidths[name] = len(z)
-					}
-					row[name] = z
-				case float64, float32:
-					ff := fmt.Sprintf("%.2f", z)
-					if len(ff) > maxWidth {
-						columnWidths[name] = len(ff)
-					}
-					row[name] = ff
-				case int, int8, int16, int32, int64:
-					ff := fmt.Sprintf("%d", z)
-					if len(ff) > maxWidth {
-						columnWidths[name] = len(ff)
-					}
-					row[name] = ff
-				}
-			}
-			rows = append(rows, row)
-		}
-	}
-	sb.WriteString(fmt.Sprintf("%s|", strings.Repeat(fs.indentStep, 3)))
-	for _, c := range columns {
-		sb.WriteString(fmt.Sprintf(" %-"+strconv.Itoa(columnWidths[c])+"s ", c)) //nolint:goconst
-		sb.WriteString("|")
+	rows, numeric, err := buildTable(items, columns)
+	if err != nil {
+		fs.t.Errorf("%s", err)
+		return
 	}
-	sb.WriteString("\n")
-
-	for i, r := range rows {
-		_ = r
-		if i != 0 {
-			sb.WriteString("\n")
-		}
-		sb.WriteString(fmt.Sprintf("%s|", strings.Repeat(fs.indentStep, 3)))
-		for _, c := range columns {
-			sb.WriteString(fmt.Sprintf(" %-"+strconv.Itoa(columnWidths[c])+"s ", r[c]))
-			_ = c
 
-			sb.WriteString("|")
-		}
-	}
+	n := &node2{}
 
 	s := &featureStep{
-		kind:  isTable,
-		title: sb.String(),
+		kind:    isTable,
+		rows:    rows,
+		numeric: numeric,
 	}
 
 	n.step = s
@@ -790,7 +743,7 @@ func (fs *FeatureSuite) table(items any, columns ...string) { //nolint:gocognit,
 func buildSuiteTitleForFeature(suite []*featureStep) string {
 	var sb strings.Builder
 	for i, s := range suite {
-		if s.kind == isFeature || s.kind == isScenario {
+		if s.kind == isFeature || s.kind == isRule || s.kind == isScenario {
 			if i != 0 {
 				sb.WriteString("/")
 			}
@@ -800,6 +753,156 @@ func buildSuiteTitleForFeature(suite []*featureStep) string {
 	return sb.String()
 }
 
+// scenarioOf returns the scenario step of a flattened suite.
+func scenarioOf(suite []*featureStep) *featureStep {
+	for _, s := range suite {
+		if s.kind == isScenario {
+			return s
+		}
+	}
+	return nil
+}
+
+// runSteps runs the given/when/then steps of a suite, and stops at the first failing
+// one, so that the steps which follow it don't produce a cascade of errors. The steps
+// which did not run get marked as skipped, also when a step stops the test via
+// [testing.T.FailNow] or [testing.T.SkipNow]. With the [StepSubtests] option, each
+// step runs in a subtest of its own, and the steps which did not run get reported as
+// skipped subtests.
+func (fs *FeatureSuite) runSteps(
+	t *testing.T,
+	w *World,
+	info ScenarioInfo,
+	suite []*featureStep,
+	run func(t *testing.T, s *featureStep),
+) {
+	t.Helper()
+
+	sc := scenarioOf(suite)
+
+	remaining := make([]*featureStep, 0, len(suite))
+	for _, s := range suite {
+		if s.kind == isGiven || s.kind == isWhen || s.kind == isThen {
+			remaining = append(remaining, s)
+		}
+	}
+
+	defer func() {
+		for _, s := range remaining {
+			fs.setStepStatus(sc, s, &featureStepRun{status: statusSkipped})
+		}
+	}()
+
+	stepSkipped := false
+	for len(remaining) > 0 && !t.Failed() && !stepSkipped {
+		current := remaining[0]
+		remaining = remaining[1:]
+
+		if !fs.stepSubtests {
+			fs.execStep(t, w, info, sc, current, run)
+			continue
+		}
+
+		t.Run(stepSubtestTitle(current), func(t *testing.T) {
+			t.Helper()
+			defer func() { stepSkipped = t.Skipped() }()
+			fs.execStep(t, w, info, sc, current, run)
+		})
+	}
+
+	if !fs.stepSubtests {
+		return
+	}
+
+	for _, s := range remaining {
+		t.Run(stepSubtestTitle(s), func(t *testing.T) {
+			t.Skip("a preceding step did not pass")
+		})
+	}
+
+	if stepSkipped {
+		t.SkipNow()
+	}
+}
+
+// execStep runs a single step of the scenario sc and records its status and the time spent
+// in it. A step which stops the test via [testing.T.SkipNow] is marked as skipped.
+func (fs *FeatureSuite) execStep(
+	t *testing.T,
+	w *World,
+	info ScenarioInfo,
+	sc, s *featureStep,
+	run func(t *testing.T, s *featureStep),
+) {
+	t.Helper()
+
+	var (
+		r        = &featureStepRun{startedAt: time.Now()}
+		finished bool
+	)
+
+	defer func() {
+		r.timeSpent = time.Since(r.startedAt)
+		r.status = statusPassed
+		switch {
+		case s.undefined:
+			r.status = statusUndefined
+		case t.Failed():
+			r.status = statusFailed
+		case !finished:
+			r.status = statusSkipped
+		}
+		fs.setStepStatus(sc, s, r)
+	}()
+
+	defer attachTo(t, &r.attachments)()
+
+	fs.runStep(t, w, info, s, func() { run(t, s) })
+	finished = true
+}
+
+// hasUndefinedStep reports whether a scenario stopped at an undefined step.
+func hasUndefinedStep(sc *featureStep) bool {
+	for _, r := range sc.runs {
+		if r.status == statusUndefined {
+			return true
+		}
+	}
+	return false
+}
+
+// stepSubtestTitle returns the title of the subtest of a step, e.g. "Given an empty cart".
+func stepSubtestTitle(s *featureStep) string {
+	return s.keyword() + " " + s.title
+}
+
+// setStepStatus records the result of a step in the scenario sc. The background steps are
+// shared by the scenarios, so the step itself reports a single one of its runs, whereby a
+// failure in any of them takes precedence over a pass, which in turn takes precedence over
+// a skip.
+func (fs *FeatureSuite) setStepStatus(sc, s *featureStep, r *featureStepRun) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if sc != nil {
+		if sc.runs == nil {
+			sc.runs = map[*featureStep]*featureStepRun{}
+		}
+		sc.runs[s] = r
+	}
+
+	switch {
+	case s.status == statusFailed || s.status == statusUndefined:
+		return
+	case s.status == statusPassed && r.status != statusFailed && r.status != statusUndefined:
+		return
+	case s.status == statusSkipped && r.status == statusSkipped:
+		return
+	}
+
+	s.status, s.timeSpent, s.attachments = r.status, r.timeSpent, r.attachments
+}
+
 // With is used for setting the options for a [FeatureSuite]. It will error if called twice.
 func (fs *FeatureSuite) With(options ...SuiteOption) *FeatureSuite {
 	for _, o := range options {
@@ -809,6 +912,19 @@ func (fs *FeatureSuite) With(options ...SuiteOption) *FeatureSuite {
 }
 
 func (fs *FeatureSuite) start() { //nolint:cyclop,gocognit
+	selected, err := parseTagExpression(os.Getenv(tagsEnv))
+	if err != nil {
+		fs.t.Errorf("invalid %s: %s", tagsEnv, err)
+		return
+	}
+
+	if fs.capture {
+		if err := capture.install(); err != nil {
+			fs.t.Errorf("failed to capture the output: %s", err)
+			fs.capture = false
+		}
+	}
+
 	fs.wg = &sync.WaitGroup{}
 	fs.wg.Add(len(fs.suites))
 	for i := fs.atSuiteIndex; i < len(fs.suites); i++ {
@@ -827,60 +943,122 @@ func (fs *FeatureSuite) start() { //nolint:cyclop,gocognit
 			world := newWorld()
 			world.t = t
 
-			if fs.parallel {
-				t.Parallel()
+			sc := scenarioOf(suite)
+			if sc != nil {
+				sc.t = t
+			}
+
+			if !selected(suiteTags(suite)) {
+				if fs.parallel {
+					defer fs.wg.Done()
+				}
 				for _, s := range suite {
 					if s.kind == isGiven || s.kind == isWhen || s.kind == isThen {
-						// s.done = func() {
-						// 	fs.wg.Done()
-						// }
-
-						world.currentFeatureStep = s
+						fs.setStepStatus(sc, s, &featureStepRun{status: statusSkipped})
+					}
+				}
+				t.Skipf("not selected by %s=%q", tagsEnv, os.Getenv(tagsEnv))
+			}
 
-						s.parallelCb(t, world)
+			info := scenarioInfoOf(suite)
 
-						world.currentFeatureStep = nil
+			if fs.parallel {
+				t.Parallel()
+				defer fs.wg.Done()
+			}
 
-						continue
+			if sc != nil {
+				started := time.Now()
+				sc.startedAt = started
+				defer func() {
+					sc.timeSpent = time.Since(started)
+					if hasUndefinedStep(sc) {
+						sc.status = statusUndefined
 					}
+				}()
+
+				if fs.capture {
+					defer captureOutput(t, world, &sc.output)()
 				}
-				fs.wg.Done()
+			}
+
+			if fs.parallel {
+				// the after hooks run also when a before hook stops the scenario
+				defer fs.afterScenario(t, world, info)
+				fs.beforeScenario(t, world, info)
+
+				scenarioT := world.t
+				fs.runSteps(t, world, info, suite, func(t *testing.T, s *featureStep) {
+					world.t = t
+					world.currentFeatureStep = s
+					defer func() {
+						world.t = scenarioT
+						world.currentFeatureStep = nil
+					}()
+
+					s.parallelCb(t, world)
+				})
 				return
 			}
 
-			for _, s := range suite {
-				if s.cb != nil {
-					if s.kind == isGiven || s.kind == isWhen || s.kind == isThen {
-						s.t = t
-					}
+			defer fs.afterScenario(t, world, info)
+			fs.beforeScenario(t, world, info)
 
-					fs.currentStep = s
+			fs.runSteps(t, world, info, suite, func(t *testing.T, s *featureStep) {
+				s.t = t
 
-					s.cb(t)
+				fs.currentStep = s
+				defer func() { fs.currentStep = nil }()
 
-					fs.currentStep = nil
-				}
-			}
+				s.cb(t)
+			})
 		})
 	}
 
-	if fs.invalid {
-		return
+	if fs.capture && !fs.parallel {
+		capture.uninstall()
+	}
+
+	valid := !fs.invalid
+	if valid {
+		fs.reportSteps()
+	}
+
+	for _, out := range fs.outputs {
+		if valid && out.format == Gherkin && len(fs.nodes) > 1 {
+			fs.t.Errorf("the %s supports a single feature per output, but %d were defined", out.format.string(), len(fs.nodes))
+			valid = false
+		}
 	}
 
 	if !fs.parallel {
+		if !valid {
+			return
+		}
 		for _, out := range fs.outputs {
 			_, _ = out.renderFeature(fs)
 		}
+		fs.writeAllureResults()
 		return
 	}
 
 	go func() {
 		fs.wg.Wait()
 
+		// the outputs are restored before the rendering, so that the done callback gets
+		// called with them being restored
+		if fs.capture {
+			capture.uninstall()
+		}
+
+		if !valid {
+			return
+		}
+
 		for _, out := range fs.outputs {
 			_, _ = out.renderFeature(fs)
 		}
+		fs.writeAllureResults()
 
 		if fs.done != nil {
 			fs.done()
@@ -914,6 +1092,31 @@ func setOutput(t testingInterface, w io.Writer, outputOptions ...OutputOption) o
 			}
 			out.indent = o
 		}
+		if o.isFormat() {
+			if out.format != undefinedOption {
+				t.Fatalf("format already set to: %s", out.format.string())
+			}
+			out.format = o
+		}
+		if o == Verify {
+			if _, ok := w.(namedWriter); !ok {
+				t.Fatalf("the %s requires the output to be a file", o.string())
+			}
+			out.verify = true
+		}
+	}
+
+	if out.verify {
+		out.colorful = false
+		out.durations = false
+	}
+
+	if out.format.isFormat() {
+		out.colorful = false
+	}
+
+	if out.format == Gherkin {
+		out.durations = false
 	}
 
 	if out.indent == undefinedOption {
@@ -0,0 +1,85 @@
+package gospec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// writeGherkin writes the node in strict Gherkin, so that the output can be saved
+// as a `.feature` file and read by standard Gherkin parsers and editors. Colors
+// and durations are never written, and the filenames (when enabled) are written
+// as comments above the respective keyword.
+func (n *node2) writeGherkin(sb *strings.Builder, level int, output *output1) {
+	var keyword string
+
+	indent := strings.Repeat(output.indentStep, level)
+
+	switch n.step.kind { //nolint:exhaustive
+	case isFeature:
+		keyword = "Feature:"
+	case isRule:
+		keyword = "Rule:"
+	case isBackground:
+		keyword = "Background:"
+	case isScenario:
+		keyword = "Scenario:"
+	case isScenarioOutline:
+		keyword = "Scenario Outline:"
+	case isExamples:
+		sb.WriteString(fmt.Sprintf("\n%sExamples:\n", indent))
+		writeTable(sb, strings.Repeat(output.indentStep, level+1), n.step.rows, nil, true)
+		return
+	case isGiven, isWhen, isThen:
+		keyword = n.step.keyword()
+	case isTable:
+		writeTable(sb, indent, n.step.rows, n.step.numeric, true)
+	case isDocString:
+		writeDocString(sb, indent, n.step.docString, true)
+	}
+
+	if keyword != "" {
+		if n.step.kind != isFeature && n.step.kind != isGiven && n.step.kind != isWhen && n.step.kind != isThen {
+			sb.WriteString("\n")
+		}
+
+		if output.printFilenames {
+			sb.WriteString(fmt.Sprintf("%s# %s:%d\n", indent, strings.TrimPrefix(n.step.file, basePath), n.step.lineNo))
+		}
+
+		writeTags(sb, indent, n.step.tags)
+
+		line := indent + keyword
+		if title := gherkinLine(n.step.title); title != "" {
+			line += " " + title
+		}
+		sb.WriteString(line + "\n")
+
+		n.writeDescription(sb, level+1, output)
+	}
+
+	for _, c := range n.children {
+		c.writeGherkin(sb, level+1, output)
+	}
+}
+
+// gherkinLine makes sure a title fits on a single line, since a line break would
+// otherwise end the step and produce an invalid Gherkin document.
+func gherkinLine(title string) string {
+	return strings.TrimSpace(strings.NewReplacer("\r\n", " ", "\n", " ").Replace(title))
+}
+
+// splitTitle splits a multi-line feature or scenario title into the title itself,
+// being the first line, and a free-form description, being the rest of the lines.
+func splitTitle(title string) (string, string) {
+	lines := strings.Split(strings.TrimSpace(title), "\n")
+	if len(lines) == 1 {
+		return title, ""
+	}
+
+	description := make([]string, 0, len(lines)-1)
+	for _, l := range lines[1:] {
+		description = append(description, strings.TrimSpace(l))
+	}
+
+	return strings.TrimSpace(lines[0]), strings.Trim(strings.Join(description, "\n"), "\n")
+}
@@ -0,0 +1,461 @@
+package gospec
+
+import (
+	"fmt"
+	"strings"
+)
+
+type gherkinFeature struct {
+	tags        []string
+	title       string
+	description string
+	lineNo      int
+	background  *gherkinBackground
+	scenarios   []*gherkinScenario
+	rules       []*gherkinRule
+}
+
+type gherkinRule struct {
+	tags        []string
+	title       string
+	description string
+	lineNo      int
+	background  *gherkinBackground
+	scenarios   []*gherkinScenario
+}
+
+type gherkinBackground struct {
+	lineNo int
+	steps  []*gherkinStep
+}
+
+type gherkinScenario struct {
+	tags        []string
+	outline     bool
+	title       string
+	description string
+	lineNo      int
+	steps       []*gherkinStep
+	examples    []*gherkinExamples
+}
+
+type gherkinExamples struct {
+	tags   []string
+	title  string
+	lineNo int
+	// rows holds the header as the first row and the example values in the rest.
+	rows [][]string
+}
+
+type gherkinStep struct {
+	keyword   string
+	text      string
+	lineNo    int
+	table     [][]string
+	docString *gherkinDocString
+}
+
+type gherkinDocString struct {
+	contentType string
+	content     string
+}
+
+// gherkinParser is a minimal parser of Gherkin documents. It supports features, rules,
+// backgrounds, scenarios and scenario outlines with examples, steps with data tables
+// and doc strings, tags, descriptions and comments. Only English keywords are supported.
+type gherkinParser struct {
+	name  string
+	lines []string
+	pos   int
+}
+
+func parseGherkin(name, src string) (*gherkinFeature, error) {
+	p := &gherkinParser{
+		name:  name,
+		lines: strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n"),
+	}
+	return p.parse()
+}
+
+func (p *gherkinParser) errorf(lineNo int, format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", p.name, lineNo, fmt.Sprintf(format, args...))
+}
+
+// next returns the next significant line, i.e. one which is neither blank nor
+// a comment, along with its line number, without consuming it.
+func (p *gherkinParser) next() (string, int, bool) {
+	for p.pos < len(p.lines) {
+		line := strings.TrimSpace(p.lines[p.pos])
+		if line == "" || strings.HasPrefix(line, "#") {
+			p.pos++
+			continue
+		}
+		return line, p.pos + 1, true
+	}
+	return "", 0, false
+}
+
+func keywordTitle(line, keyword string) (string, bool) {
+	if !strings.HasPrefix(line, keyword+":") {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, keyword+":")), true
+}
+
+func matchKeyword(line string, keywords ...string) (string, string, bool) {
+	for _, k := range keywords {
+		if title, ok := keywordTitle(line, k); ok {
+			return k, title, true
+		}
+	}
+	return "", "", false
+}
+
+var ( //nolint:gochecknoglobals
+	scenarioKeywords = []string{"Scenario Outline", "Scenario Template", "Scenario", "Example"}
+	examplesKeywords = []string{"Examples", "Scenarios"}
+	stepKeywords     = []string{"Given", "When", "Then", "And", "But", "*"}
+)
+
+func isStepLine(line string) bool {
+	for _, k := range stepKeywords {
+		if strings.HasPrefix(line, k+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlockLine(line string) bool {
+	_, _, ok := matchKeyword(line, append([]string{"Feature", "Rule", "Background"}, append(scenarioKeywords, examplesKeywords...)...)...)
+	return ok || isStepLine(line) || strings.HasPrefix(line, "@") || strings.HasPrefix(line, "|")
+}
+
+func (p *gherkinParser) tags() []string {
+	var tags []string
+	for {
+		line, _, ok := p.next()
+		if !ok || !strings.HasPrefix(line, "@") {
+			return tags
+		}
+		for _, f := range strings.Fields(line) {
+			if strings.HasPrefix(f, "#") {
+				break
+			}
+			tags = append(tags, f)
+		}
+		p.pos++
+	}
+}
+
+// description consumes the free-form lines which follow a keyword line.
+func (p *gherkinParser) description() string {
+	var lines []string
+	for p.pos < len(p.lines) {
+		line := strings.TrimSpace(p.lines[p.pos])
+		if strings.HasPrefix(line, "#") {
+			p.pos++
+			continue
+		}
+		if line != "" && isBlockLine(line) {
+			break
+		}
+		lines = append(lines, line)
+		p.pos++
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+func (p *gherkinParser) parse() (*gherkinFeature, error) {
+	tags := p.tags()
+
+	line, lineNo, ok := p.next()
+	if !ok {
+		return nil, p.errorf(len(p.lines), "expected a feature but the document is empty")
+	}
+
+	title, ok := keywordTitle(line, "Feature")
+	if !ok {
+		return nil, p.errorf(lineNo, "expected `Feature:` but got %q", line)
+	}
+	p.pos++
+
+	f := &gherkinFeature{
+		tags:        tags,
+		title:       title,
+		lineNo:      lineNo,
+		description: p.description(),
+	}
+
+	var err error
+
+	f.background, f.scenarios, err = p.scenarios()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tags = p.tags()
+		line, lineNo, ok = p.next()
+		if !ok {
+			if len(tags) > 0 {
+				return nil, p.errorf(len(p.lines), "expected a rule after the tags")
+			}
+			return f, nil
+		}
+
+		title, ok = keywordTitle(line, "Rule")
+		if !ok {
+			return nil, p.errorf(lineNo, "unexpected %q", line)
+		}
+		p.pos++
+
+		r := &gherkinRule{
+			tags:        tags,
+			title:       title,
+			lineNo:      lineNo,
+			description: p.description(),
+		}
+
+		r.background, r.scenarios, err = p.scenarios()
+		if err != nil {
+			return nil, err
+		}
+
+		f.rules = append(f.rules, r)
+	}
+}
+
+// scenarios parses an optional background followed by scenarios, up until the end
+// of the document or the next rule.
+func (p *gherkinParser) scenarios() (*gherkinBackground, []*gherkinScenario, error) {
+	var (
+		background *gherkinBackground
+		scenarios  []*gherkinScenario
+		err        error
+	)
+
+	for {
+		start := p.pos
+		tags := p.tags()
+
+		line, lineNo, ok := p.next()
+		if !ok {
+			if len(tags) > 0 {
+				return nil, nil, p.errorf(len(p.lines), "expected a scenario after the tags")
+			}
+			return background, scenarios, nil
+		}
+
+		if _, ok = keywordTitle(line, "Rule"); ok {
+			// the tags belong to the rule
+			p.pos = start
+			return background, scenarios, nil
+		}
+
+		if _, ok = keywordTitle(line, "Background"); ok {
+			if background != nil || len(scenarios) > 0 || len(tags) > 0 {
+				return nil, nil, p.errorf(lineNo, "unexpected background")
+			}
+			p.pos++
+			_ = p.description()
+			background = &gherkinBackground{lineNo: lineNo}
+			if background.steps, err = p.steps(); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		keyword, title, ok := matchKeyword(line, scenarioKeywords...)
+		if !ok {
+			return nil, nil, p.errorf(lineNo, "expected a scenario but got %q", line)
+		}
+		p.pos++
+
+		sc := &gherkinScenario{
+			tags:        tags,
+			outline:     keyword == "Scenario Outline" || keyword == "Scenario Template",
+			title:       title,
+			lineNo:      lineNo,
+			description: p.description(),
+		}
+
+		if sc.steps, err = p.steps(); err != nil {
+			return nil, nil, err
+		}
+
+		if sc.examples, err = p.examples(); err != nil {
+			return nil, nil, err
+		}
+
+		if sc.outline && len(sc.examples) == 0 {
+			return nil, nil, p.errorf(lineNo, "expected examples for the scenario outline")
+		}
+
+		if !sc.outline && len(sc.examples) > 0 {
+			return nil, nil, p.errorf(sc.examples[0].lineNo, "unexpected examples for a scenario which is not an outline")
+		}
+
+		scenarios = append(scenarios, sc)
+	}
+}
+
+func (p *gherkinParser) steps() ([]*gherkinStep, error) {
+	var steps []*gherkinStep
+
+	for {
+		line, lineNo, ok := p.next()
+		if !ok || !isStepLine(line) {
+			return steps, nil
+		}
+		p.pos++
+
+		keyword, text, _ := strings.Cut(line, " ")
+		s := &gherkinStep{
+			keyword: keyword,
+			text:    strings.TrimSpace(text),
+			lineNo:  lineNo,
+		}
+
+		line, _, ok = p.next()
+		switch {
+		case ok && strings.HasPrefix(line, "|"):
+			table, err := p.table()
+			if err != nil {
+				return nil, err
+			}
+			s.table = table
+		case ok && (strings.HasPrefix(line, `"""`) || strings.HasPrefix(line, "```")):
+			docString, err := p.docString()
+			if err != nil {
+				return nil, err
+			}
+			s.docString = docString
+		}
+
+		steps = append(steps, s)
+	}
+}
+
+func (p *gherkinParser) examples() ([]*gherkinExamples, error) {
+	var examples []*gherkinExamples
+
+	for {
+		start := p.pos
+		tags := p.tags()
+
+		line, lineNo, ok := p.next()
+		if !ok {
+			p.pos = start
+			return examples, nil
+		}
+
+		_, title, ok := matchKeyword(line, examplesKeywords...)
+		if !ok {
+			p.pos = start
+			return examples, nil
+		}
+		p.pos++
+
+		_ = p.description()
+
+		rows, err := p.table()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) == 0 {
+			return nil, p.errorf(lineNo, "expected a table for the examples")
+		}
+
+		examples = append(examples, &gherkinExamples{
+			tags:   tags,
+			title:  title,
+			lineNo: lineNo,
+			rows:   rows,
+		})
+	}
+}
+
+func (p *gherkinParser) table() ([][]string, error) {
+	var rows [][]string
+
+	for {
+		line, lineNo, ok := p.next()
+		if !ok || !strings.HasPrefix(line, "|") {
+			return rows, nil
+		}
+		p.pos++
+
+		if !strings.HasSuffix(line, "|") || len(line) == 1 {
+			return nil, p.errorf(lineNo, "inconsistent table row, expected it to end with `|`")
+		}
+
+		row := parseTableRow(line)
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return nil, p.errorf(lineNo, "inconsistent table row, expected %d cells but got %d", len(rows[0]), len(row))
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+// parseTableRow splits a table row into its (unescaped) cells.
+func parseTableRow(line string) []string {
+	var (
+		cells []string
+		cell  strings.Builder
+	)
+
+	inner := line[1 : len(line)-1]
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case c == '\\' && i+1 < len(inner):
+			i++
+			switch inner[i] {
+			case 'n':
+				cell.WriteByte('\n')
+			default:
+				cell.WriteByte(inner[i])
+			}
+		case c == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(c)
+		}
+	}
+
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func (p *gherkinParser) docString() (*gherkinDocString, error) {
+	opening := p.lines[p.pos]
+	startLineNo := p.pos + 1
+	indent := len(opening) - len(strings.TrimLeft(opening, " \t"))
+	trimmed := strings.TrimSpace(opening)
+	delimiter := trimmed[:3]
+
+	ds := &gherkinDocString{contentType: strings.TrimSpace(trimmed[3:])}
+	p.pos++
+
+	var lines []string
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		if strings.TrimSpace(line) == delimiter {
+			p.pos++
+			ds.content = strings.Join(lines, "\n")
+			return ds, nil
+		}
+
+		// remove the indentation of the opening delimiter from each line
+		for i := 0; i < indent && len(line) > 0 && (line[0] == ' ' || line[0] == '\t'); i++ {
+			line = line[1:]
+		}
+
+		lines = append(lines, strings.ReplaceAll(line, strings.Repeat(`\`+delimiter[:1], 3), delimiter))
+	}
+
+	return nil, p.errorf(startLineNo, "unterminated doc string")
+}
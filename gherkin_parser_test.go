@@ -0,0 +1,88 @@
+package gospec
+
+import (
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestParseGherkin(t *testing.T) {
+	f, err := parseGherkin("cart.feature", `
+@cart @smoke
+Feature: Cart
+  As a shopper
+
+  Background:
+    Given an empty cart
+
+  Scenario Outline: adding <count> items
+    When <count> items are added
+    Then the cart has <count> items
+
+    @small
+    Examples: small carts
+      | count |
+      | 1     |
+      | 2     |
+
+  Rule: coupons
+    Scenario: applying a coupon # with a comment
+      Given a coupon:
+        """json
+        {"code": "GOPHER"}
+        """
+      When the coupon is applied
+      But the cart is empty
+      Then the table has an escaped \| pipe:
+        | name      | value |
+        | a \| b    | c\nd  |
+`)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"@cart", "@smoke"}, f.tags)
+	assert.Equal(t, "Cart", f.title)
+	assert.Equal(t, "As a shopper", f.description)
+	assert.Equal(t, 3, f.lineNo)
+	assert.Equal(t, 7, f.background.steps[0].lineNo)
+	assert.Equal(t, "an empty cart", f.background.steps[0].text)
+
+	assert.Equal(t, 1, len(f.scenarios))
+	outline := f.scenarios[0]
+	assert.Equal(t, true, outline.outline)
+	assert.Equal(t, "adding <count> items", outline.title)
+	assert.Equal(t, 2, len(outline.steps))
+	assert.Equal(t, 1, len(outline.examples))
+	assert.Equal(t, []string{"@small"}, outline.examples[0].tags)
+	assert.Equal(t, "small carts", outline.examples[0].title)
+	assert.Equal(t, [][]string{{"count"}, {"1"}, {"2"}}, outline.examples[0].rows)
+
+	assert.Equal(t, 1, len(f.rules))
+	assert.Equal(t, "coupons", f.rules[0].title)
+	sc := f.rules[0].scenarios[0]
+	assert.Equal(t, "applying a coupon # with a comment", sc.title)
+	assert.Equal(t, []string{"Given", "When", "But", "Then"}, []string{
+		sc.steps[0].keyword, sc.steps[1].keyword, sc.steps[2].keyword, sc.steps[3].keyword,
+	})
+	assert.Equal(t, &gherkinDocString{contentType: "json", content: `{"code": "GOPHER"}`}, sc.steps[0].docString)
+	assert.Equal(t, [][]string{{"name", "value"}, {"a | b", "c\nd"}}, sc.steps[3].table)
+}
+
+func TestParseGherkinErrors(t *testing.T) {
+	for _, tc := range []struct {
+		src string
+		err string
+	}{
+		{"", "cart.feature:1: expected a feature but the document is empty"},
+		{"Scenario: a", `cart.feature:1: expected ` + "`Feature:`" + ` but got "Scenario: a"`},
+		{"Feature: a\n  Scenario Outline: b\n    Given c", "cart.feature:2: expected examples for the scenario outline"},
+		{"Feature: a\n  Scenario: b\n    Given c\n      | d | e |\n      | f |", "cart.feature:5: inconsistent table row, expected 2 cells but got 1"},
+		{"Feature: a\n  Scenario: b\n    Given c\n      \"\"\"\n      d", "cart.feature:4: unterminated doc string"},
+	} {
+		_, err := parseGherkin("cart.feature", tc.src)
+		if err == nil {
+			t.Errorf("expected an error for %q", tc.src)
+			continue
+		}
+		assert.Equal(t, tc.err, err.Error())
+	}
+}
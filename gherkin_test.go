@@ -0,0 +1,95 @@
+package gospec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestGherkinOutput(t *testing.T) {
+	var (
+		out bytes.Buffer
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, background, scenario, given, when, then, table := s.With(Output(&out, Gherkin, Colorful, PrintFilenames)).API()
+
+			type Product struct {
+				Name  string
+				Price float64
+			}
+
+			feature(`Checkout
+				As a shopper
+				I want to pay for the items in my cart`, func() {
+				background(func() {
+					given("a cart", func(t *T) {})
+				})
+
+				scenario("paying\nfor two items", func() {
+					given("two items", func(t *T) {
+						table([]Product{
+							{Name: "Gopher | toy", Price: 14.99},
+							{Name: `Crab\toy`, Price: 17.49},
+						}, "Name", "Price")
+					})
+					when("paying", func(t *T) {})
+					then("the order is\ncreated", func(t *T) {})
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, strings.Join([]string{
+		`# gherkin_test.go:27`,
+		`Feature: Checkout`,
+		`  As a shopper`,
+		`  I want to pay for the items in my cart`,
+		``,
+		`  # gherkin_test.go:30`,
+		`  Background:`,
+		`    # gherkin_test.go:31`,
+		`    Given a cart`,
+		``,
+		`  # gherkin_test.go:34`,
+		`  Scenario: paying`,
+		`    for two items`,
+		`    # gherkin_test.go:35`,
+		`    Given two items`,
+		`      | Name          | Price |`,
+		`      | Gopher \| toy | 14.99 |`,
+		`      | Crab\\toy     | 17.49 |`,
+		`    # gherkin_test.go:41`,
+		`    When paying`,
+		`    # gherkin_test.go:42`,
+		`    Then the order is created`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestGherkinOutputSupportsASingleFeature(t *testing.T) {
+	var (
+		out bytes.Buffer
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, _, _, _, _, _ := s.With(Output(&out, Gherkin)).API()
+
+			feature("feature 1", func() {})
+			feature("feature 2", func() {})
+		})
+	}()
+
+	assert.Equal(t, [][]any{{"the %s supports a single feature per output, but %d were defined", "gherkin format", 2}}, tm.calls)
+	assert.Equal(t, "", out.String())
+}
@@ -76,6 +76,9 @@ type SpecSuite struct {
 	only        bool
 	wg          *sync.WaitGroup
 	testObjects []*testing.T
+	runs        [][]stepRun
+	allureDir   string
+	capture     bool
 }
 
 // WithSpecSuite defines a new [SpecSuite] instance, by passing that new instance through the callback.
@@ -129,6 +132,11 @@ type step struct {
 	parallelCb func(t *testing.T, w *World)
 	timeSpent  time.Duration
 	done       func()
+	// attachments are the ones added via [Attach] while running an `it` block, including
+	// the beforeEach blocks preceding it.
+	attachments []*attachment
+	// output is the output captured while running a failed `it` block, see [CaptureOutput].
+	output string
 }
 
 type output1 struct {
@@ -138,6 +146,8 @@ type output1 struct {
 	printFilenames bool
 	indent         OutputOption
 	indentStep     string
+	format         OutputOption
+	verify         bool
 }
 
 func (suite *SpecSuite) failed(index int) bool {
@@ -151,11 +161,44 @@ func (suite *SpecSuite) skipped(index int) bool {
 }
 
 func (o *output1) renderSpec(s *SpecSuite) (int, error) {
-	return o.out.Write([]byte(tree(s.nodes).String(o, s)))
+	switch o.format { //nolint:exhaustive
+	case Markdown:
+		return o.write(s.t, tree(s.nodes).markdown(o, s))
+	case HTML:
+		nodes := tree(s.nodes).report(s)
+		if err := o.saveAttachments(nodes); err != nil {
+			s.t.Errorf("failed to save the attachments: %s", err)
+		}
+		return o.write(s.t, htmlReport(o, htmlReportTitle, nodes))
+	}
+	return o.write(s.t, tree(s.nodes).String(o, s))
 }
 
 func (o *output1) renderFeature(fs *FeatureSuite) (int, error) {
-	return o.out.Write([]byte(tree2(fs.nodes).String(o)))
+	switch o.format { //nolint:exhaustive
+	case Markdown:
+		return o.write(fs.t, tree2(fs.nodes).markdown(o))
+	case HTML:
+		nodes := tree2(fs.nodes).report()
+		if err := o.saveAttachments(nodes); err != nil {
+			fs.t.Errorf("failed to save the attachments: %s", err)
+		}
+		return o.write(fs.t, htmlReport(o, htmlReportTitle, nodes))
+	case CucumberJSON:
+		return o.write(fs.t, tree2(fs.nodes).cucumberJSON())
+	case CucumberMessages:
+		return o.write(fs.t, tree2(fs.nodes).cucumberMessages())
+	}
+	return o.write(fs.t, tree2(fs.nodes).String(o))
+}
+
+func (o OutputOption) isFormat() bool {
+	return o == Gherkin || o == Markdown || o == HTML || o == CucumberJSON || o == CucumberMessages
+}
+
+// featureOnly reports whether the format is supported only by feature suites.
+func (o OutputOption) featureOnly() bool {
+	return o == Gherkin || o == CucumberJSON || o == CucumberMessages
 }
 
 // NewTestSuite creates a new instance of SpecSuite.
@@ -170,11 +213,22 @@ func newSpecSuite(t *testing.T) *SpecSuite {
 }
 
 func (suite *SpecSuite) start2() {
+	if suite.capture {
+		if err := capture.install(); err != nil {
+			suite.t.Errorf("failed to capture the output: %s", err)
+			suite.capture = false
+		}
+	}
+
 	if !suite.parallel {
 		suite.start()
+		if suite.capture {
+			capture.uninstall()
+		}
 		for _, out := range suite.outputs {
 			_, _ = out.renderSpec(suite)
 		}
+		suite.writeAllureResults()
 		return
 	}
 
@@ -186,9 +240,14 @@ func (suite *SpecSuite) start2() {
 	go func() {
 		suite.wg.Wait()
 
+		if suite.capture {
+			capture.uninstall()
+		}
+
 		for _, out := range suite.outputs {
 			_, _ = out.renderSpec(suite)
 		}
+		suite.writeAllureResults()
 
 		if suite.done != nil {
 			suite.done()
@@ -251,6 +310,7 @@ func (suite *SpecSuite) start() { //nolint:gocognit,cyclop
 	suite.t.Helper()
 
 	suite.testObjects = make([]*testing.T, len(suite.suites))
+	suite.runs = make([][]stepRun, len(suite.suites))
 
 	for i, suite2 := range suite.suites {
 		suite2 := suite2
@@ -279,23 +339,36 @@ func (suite *SpecSuite) start() { //nolint:gocognit,cyclop
 			world.t = t
 
 			suite.testObjects[i] = t
+			suite.runs[i] = make([]stepRun, len(suite2))
+
+			lastStep := suite2[len(suite2)-1]
+			if lastStep.block == isIt {
+				defer attachTo(t, &lastStep.attachments)()
+			}
 
 			if suite.parallel {
 				t.Parallel()
 				defer suite.wg.Done()
-				for _, s := range suite2 {
+				if suite.capture && lastStep.block == isIt {
+					defer captureOutput(t, world, &lastStep.output)()
+				}
+				for j, s := range suite2 {
 					if s.block == isIt {
 						s.t = t
 					}
 					if s.block == isIt || s.block == isBeforeEach {
-						s.parallelCb(t, world)
+						suite.runs[i][j].track(func() { s.parallelCb(t, world) })
 						continue
 					}
 				}
 				return
 			}
 
-			for _, s := range suite2 {
+			if suite.capture && lastStep.block == isIt {
+				defer captureOutput(t, world, &lastStep.output)()
+			}
+
+			for j, s := range suite2 {
 				if s.cb == nil {
 					continue
 				}
@@ -306,7 +379,7 @@ func (suite *SpecSuite) start() { //nolint:gocognit,cyclop
 					if s.block == isIt && suite.only && !s.only && s.t != nil {
 						s.t.Skip()
 					}
-					s.cb(t)
+					suite.runs[i][j].track(func() { s.cb(t) })
 				}
 			}
 		})
@@ -688,6 +761,38 @@ const (
 	// IndentOneTab is an option for enabling one tab as the preferred indentation step.
 	IndentOneTab
 
+	// Gherkin is an option for writing the [FeatureSuite] output as strict Gherkin, which can be
+	// saved as a `.feature` file. Colors and durations are not written in this format, and the
+	// filenames (when enabled) are written as comments. The output can hold a single feature only.
+	Gherkin
+
+	// Verify is an option for keeping the documentation files in sync with the specs. Instead of
+	// writing to the output file, the rendered output (without colors and durations) is compared
+	// against the file contents and the test fails with a diff when they differ. Setting the
+	// GOSPEC_UPDATE_DOCS environment variable rewrites the file instead. The output must be an
+	// *[os.File].
+	Verify
+
+	// Markdown is an option for writing the output as a Markdown document, e.g. for publishing it
+	// in a wiki. Describe and feature blocks are written as headings, `it` blocks as checklist
+	// items, and steps as list items. With [PrintFilenames] each block links to its definition.
+	Markdown
+
+	// HTML is an option for writing the output as a self-contained HTML report, with collapsible
+	// blocks, status filters and a search by title. It needs no external assets, so it can be
+	// published as a single file, e.g. as a CI job artifact.
+	HTML
+
+	// CucumberJSON is an option for writing the [FeatureSuite] output in the cucumber-json format,
+	// which is understood by most of the Cucumber reporting tools. The steps are reported with
+	// their keywords, statuses, durations (in nanoseconds) and source locations.
+	CucumberJSON
+
+	// CucumberMessages is an option for writing the [FeatureSuite] output as a stream of Cucumber
+	// messages (newline delimited JSON), i.e. the Gherkin documents, the pickles and the test case
+	// results, as emitted by the newer Cucumber implementations.
+	CucumberMessages
+
 	invalidOption
 )
 
@@ -721,6 +826,18 @@ func (o OutputOption) string() string {
 		return "four spaces indentation"
 	case IndentOneTab:
 		return "one tab indentation"
+	case Gherkin:
+		return "gherkin format"
+	case Verify:
+		return "verify option"
+	case Markdown:
+		return "markdown format"
+	case HTML:
+		return "html format"
+	case CucumberJSON:
+		return "cucumber json format"
+	case CucumberMessages:
+		return "cucumber messages format"
 	default:
 		return "invalid option"
 	}
@@ -728,7 +845,11 @@ func (o OutputOption) string() string {
 
 func (suite *SpecSuite) setOutput(w io.Writer, outputOptions ...OutputOption) {
 	suite.t.Helper()
-	suite.outputs = append(suite.outputs, setOutput(suite.t, w, outputOptions...))
+	out := setOutput(suite.t, w, outputOptions...)
+	if out.format.featureOnly() {
+		suite.t.Fatalf("the %s is supported only by feature suites", out.format.string())
+	}
+	suite.outputs = append(suite.outputs, out)
 }
 
 func getBasePath() string {
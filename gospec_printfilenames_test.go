@@ -136,23 +136,23 @@ func TestFeatureSuiteWithPrintedFilenames(t *testing.T) {
 		`Feature: feature 1	gospec_printfilenames_test.go:84`,
 		``,
 		`  Background:	gospec_printfilenames_test.go:85`,
-		`    Given given 1	gospec_printfilenames_test.go:86`,
-		`    Given given 2	gospec_printfilenames_test.go:87`,
+		`    ✔ Given given 1	gospec_printfilenames_test.go:86`,
+		`    ✔ Given given 2	gospec_printfilenames_test.go:87`,
 		``,
-		`  Scenario: scenario 1	gospec_printfilenames_test.go:90`,
-		`    Given given 3	gospec_printfilenames_test.go:91`,
-		`    When when 1	gospec_printfilenames_test.go:92`,
-		`    Then then 1	gospec_printfilenames_test.go:93`,
+		`  ✔ Scenario: scenario 1	gospec_printfilenames_test.go:90`,
+		`    ✔ Given given 3	gospec_printfilenames_test.go:91`,
+		`    ✔ When when 1	gospec_printfilenames_test.go:92`,
+		`    ✔ Then then 1	gospec_printfilenames_test.go:93`,
 		``,
 		`Feature: feature 2	gospec_printfilenames_test.go:97`,
 		``,
 		`  Background:	gospec_printfilenames_test.go:98`,
-		`    Given given 12	gospec_printfilenames_test.go:99`,
+		`    ✔ Given given 12	gospec_printfilenames_test.go:99`,
 		``,
-		`  Scenario: scenario 11	gospec_printfilenames_test.go:102`,
-		`    Given given 13	gospec_printfilenames_test.go:103`,
-		`    When when 11	gospec_printfilenames_test.go:104`,
-		`    Then then 11	gospec_printfilenames_test.go:105`,
+		`  ✔ Scenario: scenario 11	gospec_printfilenames_test.go:102`,
+		`    ✔ Given given 13	gospec_printfilenames_test.go:103`,
+		`    ✔ When when 11	gospec_printfilenames_test.go:104`,
+		`    ✔ Then then 11	gospec_printfilenames_test.go:105`,
 		``,
 		``,
 	}, "\n"), out.String())
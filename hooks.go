@@ -0,0 +1,182 @@
+package gospec
+
+import (
+	"testing"
+)
+
+// ScenarioInfo holds the metadata of a scenario, which is passed to the scenario hooks.
+type ScenarioInfo struct {
+	Feature string
+	Rule    string
+	Title   string
+	// Tags holds the tags of the scenario, along with the inherited ones.
+	Tags []string
+	File string
+	Line int
+	// Status is one of "passed", "failed" or "skipped" in the [FeatureSuite.AfterScenario]
+	// hooks, and empty in the [FeatureSuite.BeforeScenario] ones.
+	Status string
+}
+
+// StepInfo holds the metadata of a given/when/then step, which is passed to the step hooks.
+type StepInfo struct {
+	// Kind is one of "Given", "When" or "Then", also for the And/But steps, which
+	// inherit the kind of the preceding step.
+	Kind string
+	// Keyword is the keyword the step is rendered with, e.g. "And".
+	Keyword string
+	Title   string
+	File    string
+	Line    int
+	// Status is either "passed" or "failed" in the [FeatureSuite.AfterStep] hooks,
+	// and empty in the [FeatureSuite.BeforeStep] ones.
+	Status string
+}
+
+type scenarioHook struct {
+	cb   func(*testing.T, *World, ScenarioInfo)
+	tags []string
+}
+
+type stepHook struct {
+	cb   func(*testing.T, *World, ScenarioInfo, StepInfo)
+	tags []string
+}
+
+type hooks struct {
+	beforeScenario []scenarioHook
+	afterScenario  []scenarioHook
+	beforeStep     []stepHook
+	afterStep      []stepHook
+}
+
+// BeforeScenario registers a hook which runs before each scenario, within the subtest
+// of the scenario. When tags are passed, the hook runs only for the scenarios which
+// have at least one of them. The *[World] is the one passed to the steps in parallel
+// tests, and a scenario-scoped one otherwise. The hooks run in the order of registration.
+func (fs *FeatureSuite) BeforeScenario(cb func(t *testing.T, w *World, scenario ScenarioInfo), tags ...string) {
+	fs.hooks.beforeScenario = append(fs.hooks.beforeScenario, scenarioHook{cb: cb, tags: tags})
+}
+
+// AfterScenario registers a hook which runs after each scenario, even when the scenario
+// failed, e.g. for cleaning up fixtures. See [FeatureSuite.BeforeScenario] for the tags.
+func (fs *FeatureSuite) AfterScenario(cb func(t *testing.T, w *World, scenario ScenarioInfo), tags ...string) {
+	fs.hooks.afterScenario = append(fs.hooks.afterScenario, scenarioHook{cb: cb, tags: tags})
+}
+
+// BeforeStep registers a hook which runs before each given/when/then step, including the
+// ones of the backgrounds. See [FeatureSuite.BeforeScenario] for the tags.
+func (fs *FeatureSuite) BeforeStep(cb func(t *testing.T, w *World, scenario ScenarioInfo, step StepInfo), tags ...string) {
+	fs.hooks.beforeStep = append(fs.hooks.beforeStep, stepHook{cb: cb, tags: tags})
+}
+
+// AfterStep registers a hook which runs after each given/when/then step, even when the
+// step failed. See [FeatureSuite.BeforeScenario] for the tags.
+func (fs *FeatureSuite) AfterStep(cb func(t *testing.T, w *World, scenario ScenarioInfo, step StepInfo), tags ...string) {
+	fs.hooks.afterStep = append(fs.hooks.afterStep, stepHook{cb: cb, tags: tags})
+}
+
+// hasAnyTag reports whether the hook applies to a scenario with the given tags.
+func hasAnyTag(filter, tags []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		for _, t := range tags {
+			if f == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func scenarioInfoOf(suite []*featureStep) ScenarioInfo {
+	info := ScenarioInfo{Tags: suiteTags(suite)}
+	for _, s := range suite {
+		switch s.kind { //nolint:exhaustive
+		case isFeature:
+			info.Feature = s.title
+		case isRule:
+			info.Rule = s.title
+		case isScenario:
+			info.Title = s.title
+			info.File = s.file
+			info.Line = s.lineNo
+		}
+	}
+	return info
+}
+
+func stepInfoOf(s *featureStep) StepInfo {
+	return StepInfo{
+		Kind:    s.kind.keyword(),
+		Keyword: s.keyword(),
+		Title:   s.title,
+		File:    s.file,
+		Line:    s.lineNo,
+	}
+}
+
+func (fs *FeatureSuite) beforeScenario(t *testing.T, w *World, info ScenarioInfo) {
+	t.Helper()
+	for _, h := range fs.hooks.beforeScenario {
+		if hasAnyTag(h.tags, info.Tags) {
+			h.cb(t, w, info)
+		}
+	}
+}
+
+// afterScenario runs the after scenario hooks. It's meant to be deferred, so
+// that the hooks run even when the scenario fails via [testing.T.FailNow].
+func (fs *FeatureSuite) afterScenario(t *testing.T, w *World, info ScenarioInfo) {
+	t.Helper()
+
+	info.Status = statusPassed
+	switch {
+	case t.Failed():
+		info.Status = statusFailed
+	case t.Skipped():
+		info.Status = statusSkipped
+	}
+
+	for _, h := range fs.hooks.afterScenario {
+		if hasAnyTag(h.tags, info.Tags) {
+			h.cb(t, w, info)
+		}
+	}
+}
+
+// runStep runs the step along with the step hooks, whereby the after step hooks run
+// even when the step fails via [testing.T.FailNow].
+func (fs *FeatureSuite) runStep(t *testing.T, w *World, scenario ScenarioInfo, s *featureStep, run func()) {
+	t.Helper()
+
+	if len(fs.hooks.beforeStep) == 0 && len(fs.hooks.afterStep) == 0 {
+		run()
+		return
+	}
+
+	step := stepInfoOf(s)
+	failedBefore := t.Failed()
+
+	for _, h := range fs.hooks.beforeStep {
+		if hasAnyTag(h.tags, scenario.Tags) {
+			h.cb(t, w, scenario, step)
+		}
+	}
+
+	defer func() {
+		step.Status = statusPassed
+		if !failedBefore && t.Failed() {
+			step.Status = statusFailed
+		}
+		for _, h := range fs.hooks.afterStep {
+			if hasAnyTag(h.tags, scenario.Tags) {
+				h.cb(t, w, scenario, step)
+			}
+		}
+	}()
+
+	run()
+}
@@ -0,0 +1,161 @@
+package gospec
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestHooks(t *testing.T) {
+	var (
+		tm     = &mock{t: t}
+		events []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, background, scenario, given, when, then, _ := s.API()
+			and, _ := s.AndButAPI()
+
+			s.BeforeScenario(func(t *T, w *World, sc ScenarioInfo) {
+				events = append(events, fmt.Sprintf("before scenario %q %v", sc.Title, sc.Tags))
+			})
+			s.AfterScenario(func(t *T, w *World, sc ScenarioInfo) {
+				events = append(events, fmt.Sprintf("after scenario %q %s", sc.Title, sc.Status))
+			})
+			s.AfterScenario(func(t *T, w *World, sc ScenarioInfo) {
+				events = append(events, fmt.Sprintf("after db scenario %q", sc.Title))
+			}, "@db")
+			s.BeforeStep(func(t *T, w *World, sc ScenarioInfo, st StepInfo) {
+				events = append(events, fmt.Sprintf("before %s (%s) %q", st.Keyword, st.Kind, st.Title))
+			}, "@db")
+			s.AfterStep(func(t *T, w *World, sc ScenarioInfo, st StepInfo) {
+				events = append(events, fmt.Sprintf("after %s %q", st.Status, st.Title))
+			}, "@db")
+
+			feature("Checkout", func() {
+				background(func() {
+					given("an empty cart", func(t *T) {})
+				})
+
+				scenario("paying by card", func() {
+					when("the user pays", func(t *T) {})
+					and("confirms", func(t *T) {})
+				}, "@db")
+
+				scenario("paying by voucher", func() {
+					when("the user pays", func(t *T) { t.SkipNow() })
+					then("the order is placed", func(t *T) {})
+				})
+			}, "@checkout")
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{
+		`before scenario "paying by card" [@checkout @db]`,
+		`before Given (Given) "an empty cart"`,
+		`after passed "an empty cart"`,
+		`before When (When) "the user pays"`,
+		`after passed "the user pays"`,
+		`before And (When) "confirms"`,
+		`after passed "confirms"`,
+		`after scenario "paying by card" passed`,
+		`after db scenario "paying by card"`,
+		`before scenario "paying by voucher" [@checkout]`,
+		`after scenario "paying by voucher" skipped`,
+	}, events)
+}
+
+func TestHooksInParallelMode(t *testing.T) {
+	var (
+		tm     = &mock{t: t}
+		done   = make(chan bool, 1)
+		mu     sync.Mutex
+		events = map[string][]string{}
+	)
+
+	record := func(sc ScenarioInfo, e string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events[sc.Title] = append(events[sc.Title], e)
+	}
+
+	// the scenarios run as parallel subtests of t, so they are done by the time its
+	// cleanup functions get called
+	t.Cleanup(func() {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Errorf("test timed out")
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		assert.Equal(t, map[string][]string{
+			"scenario 1": {"step given 1 passed", "after scenario 1 passed"},
+			"scenario 2": {"step given 2 passed", "after scenario 2 passed"},
+		}, events)
+	})
+
+	WithFeatureSuite(t, func(s *FeatureSuite) {
+		s.t = tm
+		feature, _, scenario, given, _, _ := s.ParallelAPI(func() { close(done) })
+
+		s.BeforeScenario(func(t *T, w *World, sc ScenarioInfo) {
+			w.Set("scenario", sc.Title)
+		})
+		s.AfterScenario(func(t *T, w *World, sc ScenarioInfo) {
+			record(sc, fmt.Sprintf("after %v %s", w.Get("scenario"), sc.Status))
+		})
+		s.AfterStep(func(t *T, w *World, sc ScenarioInfo, st StepInfo) {
+			record(sc, fmt.Sprintf("step %s %s", st.Title, st.Status))
+		})
+
+		feature("Checkout", func() {
+			scenario("scenario 1", func() {
+				given("given 1", func(t *T, w *World) {})
+			})
+			scenario("scenario 2", func() {
+				given("given 2", func(t *T, w *World) {})
+			})
+		})
+	})
+}
+
+func TestAfterScenarioHooksRunWhenABeforeScenarioHookStopsTheScenario(t *testing.T) {
+	var (
+		tm     = &mock{t: t}
+		events []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, given, _, _, _ := s.With(Output(&bytes.Buffer{})).API()
+
+			s.BeforeScenario(func(t *T, w *World, sc ScenarioInfo) {
+				events = append(events, "before scenario")
+				t.SkipNow()
+			})
+			s.AfterScenario(func(t *T, w *World, sc ScenarioInfo) {
+				events = append(events, fmt.Sprintf("after scenario %s", sc.Status))
+			})
+
+			feature("Checkout", func() {
+				scenario("paying by card", func() {
+					given("a card", func(t *T) { events = append(events, "given") })
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{"before scenario", "after scenario skipped"}, events)
+}
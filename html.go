@@ -0,0 +1,251 @@
+package gospec
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+const htmlReportTitle = "gospec report"
+
+const htmlStyle = `
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+header { margin-bottom: 1em; }
+.summary span { margin-right: 1em; }
+.node { margin: 0.2em 0 0.2em 1.2em; }
+summary { cursor: pointer; }
+.keyword { font-weight: bold; }
+.icon { display: inline-block; width: 1.2em; }
+.passed > .icon, .passed > summary > .icon { color: #1a7f37; }
+.failed > .icon, .failed > summary > .icon { color: #cf222e; }
+.skipped > .icon, .skipped > summary > .icon { color: #0969da; }
+.undefined > .icon, .undefined > summary > .icon { color: #9a6700; }
+.duration, .location { color: #6e7781; font-size: 0.9em; }
+.description { color: #57606a; white-space: pre-wrap; margin-left: 1.2em; }
+.output { background: #f6f8fa; margin: 0.3em 0 0.3em 1.2em; padding: 0.3em 0.6em; white-space: pre-wrap; }
+.failure { background: #ffebe9; border-left: 3px solid #cf222e; margin: 0.3em 0 0.3em 1.2em; padding: 0.3em 0.6em; white-space: pre-wrap; }
+table { border-collapse: collapse; margin: 0.3em 0 0.3em 2.4em; }
+.attachments { margin: 0.3em 0 0.3em 2.4em; padding-left: 1em; }
+.doc-string { background: #f6f8fa; margin: 0.3em 0 0.3em 2.4em; padding: 0.3em 0.6em; }
+td, th { border: 1px solid #d0d7de; padding: 0.1em 0.5em; }
+td.number, th.number { text-align: right; }
+.hidden { display: none; }
+`
+
+const htmlScript = `
+(function () {
+	var search = document.getElementById("search");
+	var filters = document.querySelectorAll(".filter");
+
+	function update() {
+		var term = search.value.toLowerCase();
+		var statuses = {};
+		filters.forEach(function (f) { statuses[f.value] = f.checked; });
+
+		function matches(el) {
+			var title = el.querySelector(":scope > .title, :scope > summary > .title");
+			return title !== null && title.textContent.toLowerCase().indexOf(term) >= 0;
+		}
+
+		// units are the its and the scenarios, which get filtered by their status,
+		// whilst the blocks containing them are visible when any unit in them is
+		function visit(el, matched) {
+			matched = matched || matches(el);
+			var visible = false;
+			if (el.classList.contains("unit")) {
+				visible = statuses[el.dataset.status] !== false &&
+					(matched || el.textContent.toLowerCase().indexOf(term) >= 0);
+			} else {
+				var children = el.querySelectorAll(":scope > .node");
+				visible = children.length === 0 && matched;
+				children.forEach(function (c) { visible = visit(c, matched) || visible; });
+			}
+			el.classList.toggle("hidden", !visible);
+			return visible;
+		}
+
+		document.querySelectorAll("main > .node").forEach(function (n) { visit(n, term === ""); });
+	}
+
+	search.addEventListener("input", update);
+	filters.forEach(function (f) { f.addEventListener("change", update); });
+})();
+`
+
+var htmlIcons = map[string]string{ //nolint:gochecknoglobals
+	statusPassed:    "✔",
+	statusFailed:    "⨯",
+	statusSkipped:   "-",
+	statusUndefined: "?",
+}
+
+type htmlWriter struct {
+	sb     strings.Builder
+	output *output1
+	counts map[string]int
+}
+
+// htmlReport renders the report nodes as a self-contained HTML document, i.e. one
+// without any external assets, so that it can be published as a single file.
+func htmlReport(output *output1, title string, nodes []*reportNode) string {
+	w := &htmlWriter{output: output, counts: map[string]int{}}
+
+	for _, n := range nodes {
+		w.node(n)
+	}
+	body := w.sb.String()
+	w.sb.Reset()
+
+	w.sb.WriteString("<!DOCTYPE html>\n")
+	w.sb.WriteString("<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	w.sb.WriteString(fmt.Sprintf("<title>%s</title>\n", html.EscapeString(title)))
+	w.sb.WriteString("<style>" + htmlStyle + "</style>\n")
+	w.sb.WriteString("</head>\n<body>\n<header>\n")
+	w.sb.WriteString(fmt.Sprintf("<h1>%s</h1>\n", html.EscapeString(title)))
+	w.sb.WriteString("<div class=\"summary\">")
+	for _, status := range []string{statusPassed, statusFailed, statusSkipped, statusUndefined} {
+		if status == statusUndefined && w.counts[status] == 0 {
+			continue
+		}
+		w.sb.WriteString(fmt.Sprintf(
+			"<span><label><input type=\"checkbox\" class=\"filter\" value=\"%s\" checked> %s (%d)</label></span>",
+			status, status, w.counts[status],
+		))
+	}
+	w.sb.WriteString("</div>\n")
+	w.sb.WriteString("<input id=\"search\" type=\"search\" placeholder=\"Search by title\">\n")
+	w.sb.WriteString("</header>\n<main>\n")
+	w.sb.WriteString(body)
+	w.sb.WriteString("</main>\n<script>" + htmlScript + "</script>\n</body>\n</html>\n")
+
+	return w.sb.String()
+}
+
+func (w *htmlWriter) node(n *reportNode) {
+	if n.leaf {
+		w.leaf(n)
+		return
+	}
+
+	class := "node"
+	if n.keyword == "Scenario" {
+		class += " unit"
+		w.counts[n.status]++
+	}
+
+	w.sb.WriteString(fmt.Sprintf("<details class=\"%s %s\" data-status=\"%s\" open>\n", class, n.status, n.status))
+	w.sb.WriteString("<summary>")
+	w.heading(n)
+	w.sb.WriteString("</summary>\n")
+
+	if n.description != "" {
+		w.sb.WriteString(fmt.Sprintf("<div class=\"description\">%s</div>\n", html.EscapeString(n.description)))
+	}
+
+	if n.status == statusFailed && n.keyword == "Scenario" {
+		w.failure(n)
+	}
+
+	for _, c := range n.children {
+		w.node(c)
+	}
+
+	w.sb.WriteString("</details>\n")
+}
+
+func (w *htmlWriter) leaf(n *reportNode) {
+	class := "node leaf"
+	if n.keyword == "" {
+		class += " unit"
+		w.counts[n.status]++
+	}
+
+	w.sb.WriteString(fmt.Sprintf("<div class=\"%s %s\" data-status=\"%s\">", class, n.status, n.status))
+	w.heading(n)
+	w.sb.WriteString("\n")
+
+	for _, table := range n.tables {
+		w.table(table.rows, table.numeric)
+	}
+
+	if n.docString != nil {
+		w.sb.WriteString(fmt.Sprintf("<pre class=\"doc-string\" data-content-type=\"%s\">%s</pre>\n",
+			html.EscapeString(n.docString.ContentType), html.EscapeString(n.docString.Content)))
+	}
+
+	w.attachments(n.attachments)
+
+	if n.status == statusFailed {
+		w.failure(n)
+	}
+
+	w.sb.WriteString("</div>\n")
+}
+
+func (w *htmlWriter) heading(n *reportNode) {
+	w.sb.WriteString(fmt.Sprintf("<span class=\"icon\">%s</span>", htmlIcons[n.status]))
+	if n.keyword != "" {
+		w.sb.WriteString(fmt.Sprintf("<span class=\"keyword\">%s</span> ", html.EscapeString(n.keyword)))
+	}
+	w.sb.WriteString(fmt.Sprintf("<span class=\"title\">%s</span>", html.EscapeString(n.title)))
+	if w.output.durations && (n.leaf || n.keyword == "Scenario") {
+		w.sb.WriteString(fmt.Sprintf(" <span class=\"duration\">(%dms)</span>", n.duration.Milliseconds()))
+	}
+	if w.output.printFilenames && n.file != "" {
+		w.sb.WriteString(fmt.Sprintf(" <span class=\"location\">%s</span>", html.EscapeString(n.location())))
+	}
+}
+
+func (w *htmlWriter) attachments(attachments []*reportAttachment) {
+	if len(attachments) == 0 {
+		return
+	}
+
+	w.sb.WriteString("<ul class=\"attachments\">\n")
+	for _, a := range attachments {
+		w.sb.WriteString(fmt.Sprintf("<li><a href=\"%s\" download=\"%s\" type=\"%s\">%s</a>",
+			html.EscapeString(a.href), html.EscapeString(a.name), html.EscapeString(a.mimeType), html.EscapeString(a.name)))
+		if a.truncated {
+			w.sb.WriteString(" (truncated)")
+		}
+		w.sb.WriteString("</li>\n")
+	}
+	w.sb.WriteString("</ul>\n")
+}
+
+func (w *htmlWriter) failure(n *reportNode) {
+	w.sb.WriteString("<div class=\"failure\">")
+	w.sb.WriteString(fmt.Sprintf("failed at %s", html.EscapeString(n.location())))
+	w.sb.WriteString("</div>\n")
+
+	if n.output != "" {
+		w.sb.WriteString(fmt.Sprintf("<pre class=\"output\">%s</pre>\n", html.EscapeString(n.output)))
+	}
+}
+
+func (w *htmlWriter) table(rows [][]string, numeric []bool) {
+	if len(rows) == 0 {
+		return
+	}
+
+	class := func(i int) string {
+		if i < len(numeric) && numeric[i] {
+			return " class=\"number\""
+		}
+		return ""
+	}
+
+	w.sb.WriteString("<table>\n<tr>")
+	for i, c := range rows[0] {
+		w.sb.WriteString(fmt.Sprintf("<th%s>%s</th>", class(i), html.EscapeString(c)))
+	}
+	w.sb.WriteString("</tr>\n")
+	for _, r := range rows[1:] {
+		w.sb.WriteString("<tr>")
+		for i, c := range r {
+			w.sb.WriteString(fmt.Sprintf("<td%s>%s</td>", class(i), html.EscapeString(c)))
+		}
+		w.sb.WriteString("</tr>\n")
+	}
+	w.sb.WriteString("</table>\n")
+}
@@ -0,0 +1,124 @@
+package gospec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestSpecSuiteHTMLOutput(t *testing.T) {
+	var (
+		out bytes.Buffer
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithSpecSuite(t, func(s *SpecSuite) {
+			s.t = tm
+			describe, _, it := s.With(Output(&out, HTML, Durations, PrintFilenames)).API()
+
+			describe("Cart <v2>", func() {
+				it("has no items", func(t *T) {}, Only)
+				it("has no total", func(t *T) {})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+
+	report := out.String()
+	assert.Equal(t, true, strings.HasPrefix(report, "<!DOCTYPE html>\n"))
+	assert.Equal(t, false, strings.Contains(report, "http"), "the report should not reference external assets")
+
+	for _, fragment := range []string{
+		`passed (1)`,
+		`skipped (1)`,
+		`<details class="node passed" data-status="passed" open>`,
+		`<summary><span class="icon">✔</span><span class="title">Cart &lt;v2&gt;</span> <span class="location">html_test.go:23</span></summary>`,
+		`<div class="node leaf unit passed" data-status="passed"><span class="icon">✔</span><span class="title">has no items</span> <span class="duration">(0ms)</span> <span class="location">html_test.go:24</span>`,
+		`<div class="node leaf unit skipped" data-status="skipped"><span class="icon">-</span><span class="title">has no total</span>`,
+	} {
+		assert.Equal(t, true, strings.Contains(report, fragment), fragment)
+	}
+}
+
+func TestFeatureSuiteHTMLOutput(t *testing.T) {
+	var (
+		out bytes.Buffer
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, given, when, then, table := s.With(Output(&out, HTML)).API()
+
+			type Product struct {
+				Name  string
+				Price float64
+			}
+
+			feature("Checkout", func() {
+				scenario("paying for an item", func() {
+					given("an item", func(t *T) {
+						table([]Product{{Name: "Gopher toy", Price: 14.99}}, "Name", "Price")
+					})
+					when("paying", func(t *T) {})
+					then("the order is created", func(t *T) {})
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+
+	report := out.String()
+	for _, fragment := range []string{
+		`passed (1)`,
+		`<details class="node passed" data-status="passed" open>` + "\n" +
+			`<summary><span class="icon">✔</span><span class="keyword">Feature</span> <span class="title">Checkout</span></summary>`,
+		`<details class="node unit passed" data-status="passed" open>` + "\n" +
+			`<summary><span class="icon">✔</span><span class="keyword">Scenario</span> <span class="title">paying for an item</span></summary>`,
+		`<span class="keyword">Given</span> <span class="title">an item</span>` + "\n" +
+			"<table>\n<tr><th>Name</th><th class=\"number\">Price</th></tr>\n<tr><td>Gopher toy</td><td class=\"number\">14.99</td></tr>\n</table>\n",
+	} {
+		assert.Equal(t, true, strings.Contains(report, fragment), fragment)
+	}
+}
+
+func TestHTMLOutputWithFailuresAndDurations(t *testing.T) {
+	output := setOutput(&mock{t: t}, &bytes.Buffer{}, HTML, Durations)
+
+	nodes := []*reportNode{{
+		keyword: "Feature",
+		title:   "Checkout",
+		children: []*reportNode{{
+			keyword:  "Scenario",
+			title:    "paying by card",
+			status:   statusFailed,
+			duration: 3 * time.Millisecond,
+			children: []*reportNode{{
+				keyword:  "When",
+				title:    "the user pays",
+				status:   statusFailed,
+				duration: 2 * time.Millisecond,
+				file:     "checkout_test.go",
+				lineNo:   12,
+				leaf:     true,
+			}},
+		}},
+	}}
+	nodes[0].aggregate()
+
+	report := htmlReport(&output, "Checkout", nodes)
+	for _, fragment := range []string{
+		`<span class="keyword">Scenario</span> <span class="title">paying by card</span> <span class="duration">(3ms)</span></summary>`,
+		`<span class="keyword">When</span> <span class="title">the user pays</span> <span class="duration">(2ms)</span>`,
+		`<div class="failure">failed at checkout_test.go:12</div>`,
+	} {
+		assert.Equal(t, true, strings.Contains(report, fragment), fragment)
+	}
+}
@@ -0,0 +1,221 @@
+package gospec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// StepLibrary is a set of reusable step definitions, which are defined once and used from
+// the scenarios of any [FeatureSuite] the library is passed to via the [Steps] option. The
+// steps are referenced by their titles, e.g.
+//
+//	var steps = gospec.NewStepLibrary()
+//
+//	func init() {
+//		steps.Define("a logged in user", func(t *testing.T) {
+//			/* log in a user */
+//		})
+//		steps.DefineExpression("the cart has {int} item(s)", func(t *testing.T, n int) {
+//			/* assert the cart size */
+//		})
+//	}
+//
+//	func TestCheckout(t *testing.T) {
+//		gospec.WithFeatureSuite(t, func(s *gospec.FeatureSuite) {
+//			feature, _, scenario, given, _, then, _ := s.With(gospec.Steps(steps)).API()
+//
+//			feature("Checkout", func() {
+//				scenario("paying for the cart", func() {
+//					given.Use("a logged in user")
+//					then.Use("the cart has 0 items")
+//				})
+//			})
+//		})
+//	}
+//
+// The library steps are also used by the imported Gherkin features (see [FeatureSuite.ImportFeature]),
+// while the ones defined via [FeatureSuite.Step] take precedence over them. At the end of the
+// suite, the library steps which none of its scenarios used get logged, along with the
+// steps which have no definition. The steps should be defined before the suites run.
+type StepLibrary struct {
+	steps  stepRegistry
+	errors []error
+}
+
+// NewStepLibrary returns a new, empty, [StepLibrary].
+func NewStepLibrary() *StepLibrary {
+	return &StepLibrary{}
+}
+
+// Define defines a library step which matches the given title exactly. The function has
+// the same form as the ones passed to [FeatureSuite.Step], without the captured arguments.
+func (l *StepLibrary) Define(title string, fn any) {
+	if err := l.steps.define(callerLocation(2), "^"+regexp.QuoteMeta(title)+"$", fn); err != nil {
+		l.errors = append(l.errors, err)
+		return
+	}
+	l.steps.definitions[len(l.steps.definitions)-1].expression = title
+}
+
+// DefineExpression defines a library step by a Cucumber expression, see [FeatureSuite.Define].
+func (l *StepLibrary) DefineExpression(expression string, fn any) {
+	if err := l.steps.defineExpression(callerLocation(2), expression, fn); err != nil {
+		l.errors = append(l.errors, err)
+	}
+}
+
+// ParameterType defines a custom parameter type for the Cucumber expressions of the library
+// steps defined after it via [StepLibrary.DefineExpression], see [FeatureSuite.ParameterType].
+func (l *StepLibrary) ParameterType(name, pattern string, transformer any) {
+	if err := l.steps.defineParameterType(name, pattern, transformer); err != nil {
+		l.errors = append(l.errors, err)
+	}
+}
+
+// Step defines a library step whose pattern is a regular expression, just like the
+// steps defined via [FeatureSuite.Step].
+func (l *StepLibrary) Step(pattern string, fn any) {
+	if err := l.steps.define(callerLocation(2), pattern, fn); err != nil {
+		l.errors = append(l.errors, err)
+	}
+}
+
+// Steps is an option which makes the steps of the libraries available to a [FeatureSuite].
+// Any errors in the definitions of the library steps get reported by the suite.
+//
+// The option is supported only by the [FeatureSuite].
+func Steps(libraries ...*StepLibrary) SuiteOption {
+	return func(suite SuiteInterface) {
+		switch s := suite.(type) {
+		case *SpecSuite:
+			s.t.Helper()
+			s.t.Errorf("the Steps option is supported only by feature suites")
+		case *FeatureSuite:
+			s.t.Helper()
+			for _, l := range libraries {
+				for _, err := range l.errors {
+					s.t.Errorf("%s", err)
+				}
+			}
+			s.libraries = append(s.libraries, libraries...)
+		}
+	}
+}
+
+// useStep and parallelUseStep are the callbacks which [Given.Use] and the rest pass to the
+// given/when/then functions, for adding the steps implemented by a step definition.
+func useStep(*testing.T)                 {}
+func parallelUseStep(*testing.T, *World) {}
+
+func isUseStep(cb func(*testing.T), parallelCb func(*testing.T, *World)) bool {
+	if cb != nil {
+		return reflect.ValueOf(cb).Pointer() == reflect.ValueOf(useStep).Pointer()
+	}
+	if parallelCb != nil {
+		return reflect.ValueOf(parallelCb).Pointer() == reflect.ValueOf(parallelUseStep).Pointer()
+	}
+	return false
+}
+
+// Use adds a given step, which is implemented by the matching step definition of the
+// [StepLibrary] instances passed to the suite, or by the ones defined via [FeatureSuite.Step].
+func (g Given) Use(title string) {
+	g(title, useStep)
+}
+
+// Use adds a when step, see [Given.Use].
+func (w When) Use(title string) {
+	w(title, useStep)
+}
+
+// Use adds a then step, see [Given.Use].
+func (t Then) Use(title string) {
+	t(title, useStep)
+}
+
+// Use adds a given step in parallel tests, see [Given.Use].
+func (g ParallelGiven) Use(title string) {
+	g(title, parallelUseStep)
+}
+
+// Use adds a when step in parallel tests, see [Given.Use].
+func (w ParallelWhen) Use(title string) {
+	w(title, parallelUseStep)
+}
+
+// Use adds a then step in parallel tests, see [Given.Use].
+func (t ParallelThen) Use(title string) {
+	t(title, parallelUseStep)
+}
+
+// matchStep returns the step definition matching the title, looking it up in the steps
+// of the suite first, and in the step libraries after that. It keeps track of the used
+// library steps, and of the undefined steps, for reporting them at the end of the suite.
+func (fs *FeatureSuite) matchStep(at sourceLocation, kind featureStepKind, title string, argument any) (*stepDefinition, []string, error) {
+	d, captures, err := fs.steps.match(title)
+
+	for _, l := range fs.libraries {
+		if !errors.Is(err, errUndefinedStep) {
+			break
+		}
+		d, captures, err = l.steps.match(title)
+	}
+
+	if errors.Is(err, errUndefinedStep) {
+		fs.undefinedSteps = append(fs.undefinedSteps, undefinedStep{at: at, kind: kind, title: title, argument: argument})
+	}
+
+	if err == nil {
+		if fs.usedSteps == nil {
+			fs.usedSteps = map[*stepDefinition]bool{}
+		}
+		fs.usedSteps[d] = true
+	}
+
+	return d, captures, err
+}
+
+// reportSteps reports the library steps which none of the scenarios of the suite used, and
+// the steps which have no definition, along with snippets for implementing them. They are
+// written to the standard error, so they show up also when the tests pass.
+func (fs *FeatureSuite) reportSteps() {
+	var unused []string
+	for _, l := range fs.libraries {
+		for _, d := range l.steps.definitions {
+			if !fs.usedSteps[d] {
+				unused = append(unused, fmt.Sprintf("%s (%s:%d)", d.expression, strings.TrimPrefix(d.at.file, basePath), d.at.lineNo))
+			}
+		}
+	}
+
+	if len(unused) > 0 {
+		_, _ = fmt.Fprintf(fs.stderr, "unused library steps:\n\t%s\n", strings.Join(unused, "\n\t"))
+	}
+
+	if len(fs.undefinedSteps) == 0 {
+		return
+	}
+
+	var (
+		undefined = make([]string, 0, len(fs.undefinedSteps))
+		snippets  []string
+		seen      = map[string]bool{}
+	)
+
+	for _, u := range fs.undefinedSteps {
+		undefined = append(undefined, fmt.Sprintf("%s %s (%s:%d)",
+			u.kind.keyword(), u.title, strings.TrimPrefix(u.at.file, basePath), u.at.lineNo))
+
+		if snippet := u.snippet(fs.parallel); !seen[snippet] {
+			seen[snippet] = true
+			snippets = append(snippets, snippet)
+		}
+	}
+
+	_, _ = fmt.Fprintf(fs.stderr, "undefined steps:\n\t%s\n\nthey can be implemented via the following snippets:\n\n%s",
+		strings.Join(undefined, "\n\t"), strings.Join(snippets, "\n"))
+}
@@ -0,0 +1,144 @@
+package gospec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestStepLibrary(t *testing.T) {
+	t.Setenv(tagsEnv, "not @wip")
+
+	var (
+		out    bytes.Buffer
+		stderr bytes.Buffer
+		tm     = &mock{t: t}
+		calls  []string
+		steps  = NewStepLibrary()
+	)
+
+	steps.Define("a logged in user", func(t *T) { calls = append(calls, "logged in") })
+	steps.Step(`^the user adds (\d+) items$`, func(t *T, n int) { calls = append(calls, strings.Repeat("item ", n)) })
+	steps.Define("an admin", func(t *T) {})
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t, s.stderr = tm, &stderr
+			feature, _, scenario, given, when, then, _ := s.With(Output(&out), Steps(steps)).API()
+
+			s.Step(`^the cart has (\d+) items$`, func(t *T, n int) { calls = append(calls, "cart") })
+
+			feature("Cart", func() {
+				scenario("adding items", func() {
+					given.Use("a logged in user")
+					when.Use("the user adds 2 items")
+					then.Use("the cart has 2 items")
+				})
+
+				scenario("removing items", func() {
+					given.Use("a logged out user")
+				}, "@wip")
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{"logged in", "item item ", "cart"}, calls)
+	assert.Equal(t, [][]any(nil), tm.logs)
+	assert.Equal(t, strings.Join([]string{
+		"unused library steps:",
+		"\tan admin (library_test.go:24)",
+		"undefined steps:",
+		"\tGiven a logged out user (library_test.go:41)",
+		"",
+		"they can be implemented via the following snippets:",
+		"",
+		"s.Define(\"a logged out user\", func(t *testing.T) {",
+		"\tt.Skip(\"pending\")",
+		"})",
+		"",
+	}, "\n"), stderr.String())
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Cart`,
+		``,
+		`  ✔ Scenario: adding items`,
+		`    ✔ Given a logged in user`,
+		`    ✔ When the user adds 2 items`,
+		`    ✔ Then the cart has 2 items`,
+		``,
+		`  @wip`,
+		`  - Scenario: removing items`,
+		`    - Given a logged out user`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestStepLibraryErrors(t *testing.T) {
+	tm := &mock{t: t}
+	steps := NewStepLibrary()
+
+	steps.Step(`^(unclosed$`, func(t *T) {})
+	steps.Define("a user", func() {})
+
+	WithFeatureSuite(t, func(s *FeatureSuite) {
+		s.t = tm
+		s.With(Steps(steps))
+	})
+
+	assert.Equal(t, 2, len(tm.calls))
+	assert.Equal(t, "expected the step definition for \"^a user$\" to be a function with *testing.T as first argument and no return values", tm.calls[1][1].(error).Error())
+}
+
+func TestStepLibraryDefinesTitlesAndExpressions(t *testing.T) {
+	var (
+		tm    = &mock{t: t}
+		calls []any
+		steps = NewStepLibrary()
+	)
+
+	steps.Define("a {word} title (exactly)", func(t *T) { calls = append(calls, "exact") })
+	steps.DefineExpression("the cart has {int} item(s)", func(t *T, n int) { calls = append(calls, n) })
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, given, _, then, _ := s.With(Output(&bytes.Buffer{}), Steps(steps)).API()
+
+			feature("Cart", func() {
+				scenario("adding items", func() {
+					given.Use("a {word} title (exactly)")
+					then.Use("the cart has 2 items")
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, [][]any(nil), tm.logs)
+	assert.Equal(t, []any{"exact", 2}, calls)
+}
+
+func TestStepsWithoutACallbackAreReported(t *testing.T) {
+	tm := &mock{t: t}
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, given, _, _, _ := s.With(Output(&bytes.Buffer{})).API()
+
+			feature("Cart", func() {
+				scenario("adding items", func() {
+					given("a logged in user", nil)
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any{{
+		"%s:%d: missing callback for the `%s` step %q, the steps implemented by a step definition are added via `%s.Use`",
+		"library_test.go", 134, "Given", "a logged in user", "Given",
+	}}, tm.calls)
+}
@@ -0,0 +1,193 @@
+package gospec
+
+import (
+	"fmt"
+	"strings"
+)
+
+type markdownWriter struct {
+	sb     strings.Builder
+	output *output1
+	// inList is set while writing consecutive list items, since a list needs
+	// to be separated by a blank line from the blocks which follow it.
+	inList bool
+}
+
+func (w *markdownWriter) heading(level int, title string, s sourceLocation) {
+	if w.sb.Len() > 0 {
+		w.sb.WriteString("\n")
+	}
+	w.sb.WriteString(fmt.Sprintf("%s %s%s\n", strings.Repeat("#", min(level, 6)), title, w.link(s)))
+	w.inList = false
+}
+
+func (w *markdownWriter) paragraph(text string) {
+	w.sb.WriteString(fmt.Sprintf("\n%s\n", text))
+	w.inList = false
+}
+
+func (w *markdownWriter) listItem(text string) {
+	if !w.inList {
+		w.sb.WriteString("\n")
+	}
+	w.sb.WriteString(fmt.Sprintf("- %s\n", text))
+	w.inList = true
+}
+
+// table writes the rows as a Markdown table nested in the last list item, with the
+// numeric columns right-aligned.
+func (w *markdownWriter) table(rows [][]string, numeric []bool) {
+	if len(rows) == 0 {
+		return
+	}
+
+	line := func(cells []string) {
+		escaped := make([]string, 0, len(cells))
+		for _, c := range cells {
+			escaped = append(escaped, strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(c))
+		}
+		w.sb.WriteString(fmt.Sprintf("  | %s |\n", strings.Join(escaped, " | ")))
+	}
+
+	w.sb.WriteString("\n")
+	line(rows[0])
+	separator := make([]string, len(rows[0]))
+	for i := range separator {
+		separator[i] = "---"
+		if i < len(numeric) && numeric[i] {
+			separator[i] = "---:"
+		}
+	}
+	line(separator)
+	for _, r := range rows[1:] {
+		line(r)
+	}
+	w.sb.WriteString("\n")
+	w.inList = true
+}
+
+// codeBlock writes the doc string as a fenced code block nested in the last list item.
+func (w *markdownWriter) codeBlock(doc *DocString) {
+	fence := "```"
+	for strings.Contains(doc.Content, fence) {
+		fence += "`"
+	}
+
+	w.sb.WriteString(fmt.Sprintf("\n  %s%s\n", fence, doc.ContentType))
+	for _, l := range strings.Split(doc.Content, "\n") {
+		if l == "" {
+			w.sb.WriteString("\n")
+			continue
+		}
+		w.sb.WriteString("  " + l + "\n")
+	}
+	w.sb.WriteString(fmt.Sprintf("  %s\n\n", fence))
+	w.inList = true
+}
+
+// link returns a relative link to the source location of a block, when
+// the filenames are enabled for the output.
+func (w *markdownWriter) link(s sourceLocation) string {
+	if !w.output.printFilenames || s.file == "" {
+		return ""
+	}
+	file := strings.TrimPrefix(s.file, basePath)
+	return fmt.Sprintf(" ([%s:%d](%s#L%d))", file, s.lineNo, file, s.lineNo)
+}
+
+func (t tree) markdown(output *output1, suite *SpecSuite) string {
+	w := &markdownWriter{output: output}
+	for _, n := range t {
+		n.writeMarkdown(w, 1, suite)
+	}
+	return w.sb.String()
+}
+
+func (n *node) writeMarkdown(w *markdownWriter, level int, suite *SpecSuite) {
+	location := sourceLocation{file: n.step.file, lineNo: n.step.lineNo}
+
+	if n.step.block == isDescribe {
+		w.heading(level, n.step.title, location)
+	}
+
+	if n.step.block == isIt {
+		text := fmt.Sprintf("[x] %s", n.step.title)
+		switch {
+		case n.failed(suite):
+			text = fmt.Sprintf("[ ] %s (failed)", n.step.title)
+		case n.skipped(suite):
+			text = fmt.Sprintf("[ ] ~~%s~~ (skipped)", n.step.title)
+		}
+		if n.step.only {
+			text += " (only)"
+		}
+		if w.output.durations {
+			text += fmt.Sprintf(" (%dms)", n.step.timeSpent.Milliseconds())
+		}
+		w.listItem(text + w.link(location))
+	}
+
+	for _, c := range n.children {
+		c.writeMarkdown(w, level+1, suite)
+	}
+}
+
+func (t tree2) markdown(output *output1) string {
+	w := &markdownWriter{output: output}
+	for _, n := range t {
+		n.writeMarkdown(w, 1)
+	}
+	return w.sb.String()
+}
+
+func (n *node2) writeMarkdown(w *markdownWriter, level int) {
+	location := sourceLocation{file: n.step.file, lineNo: n.step.lineNo}
+
+	switch n.step.kind { //nolint:exhaustive
+	case isFeature:
+		w.heading(level, "Feature: "+n.step.title, location)
+	case isRule:
+		w.heading(level, "Rule: "+n.step.title, location)
+	case isBackground:
+		w.heading(level, "Background", location)
+	case isScenario:
+		w.heading(level, "Scenario: "+n.step.title, location)
+	case isScenarioOutline:
+		w.heading(level, "Scenario Outline: "+n.step.title, location)
+	case isExamples:
+		w.heading(level, "Examples", sourceLocation{})
+		rows := [][]string{append([]string{""}, n.step.rows[0]...)}
+		for i, r := range n.step.rows[1:] {
+			check := "[ ]"
+			if scenarioStatus(n.children[i].step) == statusPassed {
+				check = "[x]"
+			}
+			rows = append(rows, append([]string{check}, r...))
+		}
+		w.table(rows, nil)
+		return
+	case isGiven, isWhen, isThen:
+		text := "**" + n.step.keyword() + "** " + n.step.title
+		switch n.step.status {
+		case statusFailed:
+			text += " (failed)"
+		case statusSkipped:
+			text = "~~" + text + "~~ (skipped)"
+		case statusUndefined:
+			text += " (undefined)"
+		}
+		w.listItem(text + w.link(location))
+	case isTable:
+		w.table(n.step.rows, n.step.numeric)
+	case isDocString:
+		w.codeBlock(n.step.docString)
+	}
+
+	if n.step.description != "" {
+		w.paragraph(n.step.description)
+	}
+
+	for _, c := range n.children {
+		c.writeMarkdown(w, level+1)
+	}
+}
@@ -0,0 +1,103 @@
+package gospec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestSpecSuiteMarkdownOutput(t *testing.T) {
+	var (
+		out bytes.Buffer
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithSpecSuite(t, func(s *SpecSuite) {
+			s.t = tm
+			describe, _, it := s.With(Output(&out, Markdown, PrintFilenames)).API()
+
+			describe("Cart", func() {
+				describe("when empty", func() {
+					it("has no items", func(t *T) {}, Only)
+					it("has no total", func(t *T) {})
+				})
+				it("can be created", func(t *T) {}, Only)
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, strings.Join([]string{
+		`# Cart ([markdown_test.go:22](markdown_test.go#L22))`,
+		``,
+		`## when empty ([markdown_test.go:23](markdown_test.go#L23))`,
+		``,
+		`- [x] has no items (only) ([markdown_test.go:24](markdown_test.go#L24))`,
+		`- [ ] ~~has no total~~ (skipped) ([markdown_test.go:25](markdown_test.go#L25))`,
+		`- [x] can be created (only) ([markdown_test.go:27](markdown_test.go#L27))`,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestFeatureSuiteMarkdownOutput(t *testing.T) {
+	var (
+		out bytes.Buffer
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, background, scenario, given, when, then, table := s.With(Output(&out, Markdown)).API()
+
+			type Product struct {
+				Name  string
+				Price float64
+			}
+
+			feature("Checkout\nAs a shopper I want to pay for my cart", func() {
+				background(func() {
+					given("a cart", func(t *T) {})
+				})
+
+				scenario("paying for two items", func() {
+					given("two items", func(t *T) {
+						table([]Product{
+							{Name: "Gopher | toy", Price: 14.99},
+							{Name: "Crab toy", Price: 17.49},
+						}, "Name", "Price")
+					})
+					when("paying", func(t *T) {})
+					then("the order is created", func(t *T) {})
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, strings.Join([]string{
+		`# Feature: Checkout`,
+		``,
+		`As a shopper I want to pay for my cart`,
+		``,
+		`## Background`,
+		``,
+		`- **Given** a cart`,
+		``,
+		`## Scenario: paying for two items`,
+		``,
+		`- **Given** two items`,
+		``,
+		`  | Name | Price |`,
+		`  | --- | ---: |`,
+		`  | Gopher \| toy | 14.99 |`,
+		`  | Crab toy | 17.49 |`,
+		``,
+		`- **When** paying`,
+		`- **Then** the order is created`,
+		``,
+	}, "\n"), out.String())
+}
@@ -8,6 +8,7 @@ import (
 type mock struct {
 	t          *testing.T
 	calls      [][]any
+	logs       [][]any
 	testTitles []string
 	childMocks []*mock
 }
@@ -41,6 +42,13 @@ func (m *mock) Errorf(format string, args ...interface{}) {
 	m.calls = append(m.calls, call)
 }
 
+func (m *mock) Logf(format string, args ...interface{}) {
+	var call []any
+	call = append(call, format)
+	call = append(call, args...)
+	m.logs = append(m.logs, call)
+}
+
 func (m *mock) Run(name string, f func(t *testing.T)) bool {
 	m.testTitles = append(m.testTitles, name)
 	m.t.Run(name, func(t *testing.T) {
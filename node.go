@@ -59,7 +59,7 @@ func (n *node) write(sb *strings.Builder, indent int, output *output1, suite *Sp
 		args[3] = "✔ "
 	}
 
-	if n.step.block == isIt && ((n.step.t != nil && n.step.t.Skipped()) || suite.skipped(n.step.index)) {
+	if n.skipped(suite) {
 		if output.colorful {
 			args[2] = cyan
 			args[5] = cyan
@@ -67,7 +67,7 @@ func (n *node) write(sb *strings.Builder, indent int, output *output1, suite *Sp
 		args[3] = "[skip] "
 	}
 
-	if n.step.block == isIt && ((n.step.t != nil && n.step.t.Failed()) || suite.failed(n.step.index)) {
+	if n.failed(suite) {
 		if output.colorful {
 			args[2] = red
 			args[5] = red
@@ -91,7 +91,19 @@ func (n *node) write(sb *strings.Builder, indent int, output *output1, suite *Sp
 	sb.WriteString(fmt.Sprintf(format, args...))
 	sb.WriteString("\n")
 
+	if n.failed(suite) {
+		writeCapturedOutput(sb, indent+1, output, n.step.output)
+	}
+
 	for _, c := range n.children {
 		c.write(sb, indent+1, output, suite)
 	}
 }
+
+func (n *node) skipped(suite *SpecSuite) bool {
+	return n.step.block == isIt && ((n.step.t != nil && n.step.t.Skipped()) || suite.skipped(n.step.index))
+}
+
+func (n *node) failed(suite *SpecSuite) bool {
+	return n.step.block == isIt && ((n.step.t != nil && n.step.t.Failed()) || suite.failed(n.step.index))
+}
@@ -21,7 +21,7 @@ func (t tree2) String(output *output1) string {
 	return sb.String()
 }
 
-func (n *node2) feature(output *output1) (string, []any) {
+func (n *node2) feature(output *output1, indent string) (string, []any) {
 	format := "%sFeature:%s %s"
 	args := []any{"", "", n.step.title}
 	if output.colorful {
@@ -31,9 +31,9 @@ func (n *node2) feature(output *output1) (string, []any) {
 	return format, args
 }
 
-func (n *node2) background(output *output1) (string, []any) {
+func (n *node2) background(output *output1, indent string) (string, []any) {
 	format := "\n%s%sBackground:%s"
-	args := []any{output.indentStep, "", ""}
+	args := []any{indent, "", ""}
 	if output.colorful {
 		args[1] = bold
 		args[2] = noBold
@@ -41,9 +41,9 @@ func (n *node2) background(output *output1) (string, []any) {
 	return format, args
 }
 
-func (n *node2) scenario(output *output1) (string, []any) {
+func (n *node2) scenario(output *output1, indent string) (string, []any) {
 	format := "\n%s%sScenario:%s %s"
-	args := []any{output.indentStep, "", "", n.step.title}
+	args := []any{indent, "", "", n.step.title}
 	if output.colorful {
 		args[1] = bold
 		args[2] = noBold
@@ -51,48 +51,98 @@ func (n *node2) scenario(output *output1) (string, []any) {
 	return format, args
 }
 
-func (n *node2) given(output *output1) (string, []any) {
-	format := "%s%sGiven%s %s"
-	args := []any{strings.Repeat(output.indentStep, 2), "", "", n.step.title}
+func (n *node2) rule(output *output1, indent string) (string, []any) {
+	format := "\n%s%sRule:%s %s"
+	args := []any{indent, "", "", n.step.title}
+	if output.colorful {
+		args[1] = bold
+		args[2] = noBold
+	}
+	return format, args
+}
+
+func (n *node2) scenarioOutline(output *output1, indent string) (string, []any) {
+	format := "\n%s%sScenario Outline:%s %s"
+	args := []any{indent, "", "", n.step.title}
+	if output.colorful {
+		args[1] = bold
+		args[2] = noBold
+	}
+	return format, args
+}
+
+func (n *node2) given(output *output1, indent string) (string, []any) {
+	format := "%s%s%s%s %s"
+	args := []any{indent, "", n.step.keyword(), "", n.step.title}
 	if output.colorful {
 		args[1] = cyan
-		args[2] = noColor
+		args[3] = noColor
 	}
 	return format, args
 }
 
-func (n *node2) when(output *output1) (string, []any) {
-	format := "%s%sWhen%s %s"
-	args := []any{strings.Repeat(output.indentStep, 2), "", "", n.step.title}
+func (n *node2) when(output *output1, indent string) (string, []any) {
+	format := "%s%s%s%s %s"
+	args := []any{indent, "", n.step.keyword(), "", n.step.title}
 	if output.colorful {
 		args[1] = green
-		args[2] = noColor
+		args[3] = noColor
 	}
 	return format, args
 }
 
-func (n *node2) then(output *output1) (string, []any) {
-	format := "%s%sThen%s %s"
-	args := []any{strings.Repeat(output.indentStep, 2), "", "", n.step.title}
+func (n *node2) then(output *output1, indent string) (string, []any) {
+	format := "%s%s%s%s %s"
+	args := []any{indent, "", n.step.keyword(), "", n.step.title}
 	if output.colorful {
 		args[1] = yellow
-		args[2] = noColor
+		args[3] = noColor
 	}
 	return format, args
 }
 
 func (n *node2) write(sb *strings.Builder, indent int, output *output1) {
-	m := map[featureStepKind]func(output *output1) (string, []any){
-		isFeature:    n.feature,
-		isBackground: n.background,
-		isScenario:   n.scenario,
-		isGiven:      n.given,
-		isWhen:       n.when,
-		isThen:       n.then,
+	if output.format == Gherkin {
+		n.writeGherkin(sb, indent, output)
+		return
+	}
+
+	prefix := strings.Repeat(output.indentStep, indent)
+
+	m := map[featureStepKind]func(output *output1, indent string) (string, []any){
+		isFeature:         n.feature,
+		isRule:            n.rule,
+		isBackground:      n.background,
+		isScenario:        n.scenario,
+		isScenarioOutline: n.scenarioOutline,
+		isGiven:           n.given,
+		isWhen:            n.when,
+		isThen:            n.then,
+	}
+
+	if n.step.kind == isExamples {
+		n.writeExamples(sb, indent, output)
+		return
 	}
 
 	if f, ok := m[n.step.kind]; ok {
-		format, args := f(output)
+		format, args := f(output, prefix)
+
+		n.markStatus(output, args)
+
+		if len(n.step.tags) > 0 {
+			// the tag line goes between the blank line and the keyword
+			if strings.HasPrefix(format, "\n") {
+				sb.WriteString("\n")
+				format = format[1:]
+			}
+			writeTags(sb, prefix, n.step.tags)
+		}
+
+		if output.durations && n.status() != "" {
+			format += " (%dms)"
+			args = append(args, n.step.timeSpent.Milliseconds())
+		}
 
 		if output.printFilenames {
 			format += "\t%s:%d"
@@ -101,20 +151,83 @@ func (n *node2) write(sb *strings.Builder, indent int, output *output1) {
 
 		format += "\n"
 		sb.WriteString(fmt.Sprintf(format, args...))
+
+		n.writeDescription(sb, indent+1, output)
 	}
 
 	if n.step.kind == isTable {
-		lines := strings.Split(n.step.title, "\n")
-		for _, l := range lines {
-			sb.WriteString(
-				fmt.Sprintf("%s%s\n",
-					strings.Repeat(output.indentStep, 3), l,
-				),
-			)
-		}
+		writeTable(sb, prefix, n.step.rows, n.step.numeric, false)
+	}
+
+	if n.step.kind == isDocString {
+		writeDocString(sb, prefix, n.step.docString, false)
 	}
 
 	for _, c := range n.children {
 		c.write(sb, indent+1, output)
 	}
+
+	if n.step.kind == isScenario && n.status() == statusFailed {
+		writeCapturedOutput(sb, indent+1, output, n.step.output)
+	}
+}
+
+// status returns the status of an executed scenario or given/when/then step, or an
+// empty string for the rest of the nodes.
+func (n *node2) status() string {
+	switch n.step.kind { //nolint:exhaustive
+	case isScenario:
+		return scenarioStatus(n.step)
+	case isGiven, isWhen, isThen:
+		return n.step.status
+	}
+	return ""
+}
+
+// markStatus prefixes the scenario headers and the step lines with the status icon, as
+// done for the `it` blocks of the spec suites. The skipped steps get dimmed as a whole.
+// The args are the ones of the scenario and step formats.
+func (n *node2) markStatus(output *output1, args []any) {
+	var icon, color string
+
+	switch n.status() {
+	case statusPassed:
+		icon, color = "✔", green
+	case statusFailed:
+		icon, color = "⨯", red
+	case statusSkipped:
+		icon, color = "-", gray
+	case statusUndefined:
+		icon, color = "?", yellow
+	default:
+		return
+	}
+
+	if !output.colorful {
+		args[0] = fmt.Sprintf("%s%s ", args[0], icon)
+		return
+	}
+
+	if n.step.kind != isScenario && n.status() == statusSkipped {
+		args[0] = fmt.Sprintf("%s%s%s ", args[0], gray, icon)
+		args[1], args[3] = "", ""
+		args[4] = fmt.Sprintf("%s%s", args[4], noColor)
+		return
+	}
+
+	args[0] = fmt.Sprintf("%s%s%s%s ", args[0], color, icon, noColor)
+}
+
+func (n *node2) writeDescription(sb *strings.Builder, level int, output *output1) {
+	if n.step.description == "" {
+		return
+	}
+
+	for _, l := range strings.Split(n.step.description, "\n") {
+		if l == "" {
+			sb.WriteString("\n")
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s%s\n", strings.Repeat(output.indentStep, level), l))
+	}
 }
@@ -6,7 +6,7 @@ import (
 )
 
 // SuiteOption is a type defining an option for controlling the behaviour of [SpecSuite] or [FeatureSuite] instances.
-// The available option is: [Output].
+// The available options are: [Output], [AllureResults], [CaptureOutput], [StepSubtests], [Steps] and [Strict].
 type SuiteOption func(suiteInterface SuiteInterface)
 
 // SuiteInterface is an interface implemented by both [SpecSuite] and [FeatureSuite] suites. It is internal
@@ -25,6 +25,12 @@ type SuiteInterface interface {
 //   - [IndentTwoSpaces]
 //   - [IndentFourSpaces]
 //   - [IndentOneTab]
+//   - [Gherkin]
+//   - [Verify]
+//   - [Markdown]
+//   - [HTML]
+//   - [CucumberJSON]
+//   - [CucumberMessages]
 //
 // If there is no Output option specified, by default, the output would get printed in [os.Stdout], with the [Colorful], [Durations] and [IndentTwoSpaces] enabled.
 // When a single Output option is defined, it will overwrite the default setting entirely.
@@ -63,3 +69,85 @@ func defaultOutput() output1 {
 		indentStep: indentTwoSpaces,
 	}
 }
+
+// StepSubtests is an option which makes a [FeatureSuite] run each given/when/then step in a
+// subtest of its own, named after the keyword and the title of the step, e.g.
+//
+//	go test -run 'TestCheckout/Checkout/paying_by_card/Then_the_order_is_placed'
+//
+// This way, a failure gets attributed to the exact step in the `go test -json` output. When a
+// step fails, the steps which follow it are reported as skipped subtests.
+//
+// The option is supported only by the [FeatureSuite].
+func StepSubtests() SuiteOption {
+	return func(suite SuiteInterface) {
+		switch s := suite.(type) {
+		case *SpecSuite:
+			s.t.Helper()
+			s.t.Errorf("the StepSubtests option is supported only by feature suites")
+		case *FeatureSuite:
+			s.t.Helper()
+			s.stepSubtests = true
+		}
+	}
+}
+
+// Strict is an option which makes the scenarios of a [FeatureSuite] fail when they have an
+// undefined step, i.e. a step without a step definition (see [FeatureSuite.Define]). By
+// default, such scenarios get skipped and marked as undefined in the output.
+//
+// The option is supported only by the [FeatureSuite].
+func Strict() SuiteOption {
+	return func(suite SuiteInterface) {
+		switch s := suite.(type) {
+		case *SpecSuite:
+			s.t.Helper()
+			s.t.Errorf("the Strict option is supported only by feature suites")
+		case *FeatureSuite:
+			s.t.Helper()
+			s.strict = true
+		}
+	}
+}
+
+// AllureResults is an option for writing the results of a [SpecSuite] or a [FeatureSuite] into
+// the dir directory in the Allure results format, i.e. a `<uuid>-result.json` file per test and
+// a `<uuid>-container.json` file per top level describe or feature block, which can be rendered
+// by the Allure tools, e.g.
+//
+//	allure generate <dir>
+//
+// The describe, feature and rule blocks are reported as suites, the `it` blocks and the
+// scenarios as test cases, and the beforeEach blocks and the given/when/then steps as their
+// steps. The directory gets created when it does not exist.
+func AllureResults(dir string) SuiteOption {
+	return func(suite SuiteInterface) {
+		switch s := suite.(type) {
+		case *SpecSuite:
+			s.allureDir = dir
+		case *FeatureSuite:
+			s.allureDir = dir
+		}
+	}
+}
+
+// CaptureOutput is an option for capturing the output written by each `it` block or scenario
+// while it runs, i.e. the output written to [os.Stdout], [os.Stderr], via the [log] package or
+// via a [SlogHandler]. The captured output of the failed ones is shown under them in the output
+// and in the [HTML] and [AllureResults] reports, and is attached to their failed step in the
+// [CucumberJSON] and [CucumberMessages] outputs, whilst the output of the rest is thrown away.
+//
+// The process-wide outputs can't tell which of the specs running in parallel wrote to them, so
+// while more than one spec is running, their output is not captured but written to the original
+// outputs. The parallel specs should log via the [World.SlogHandler] of their World instead,
+// whose records are captured only into the output of the spec the World belongs to.
+func CaptureOutput() SuiteOption {
+	return func(suite SuiteInterface) {
+		switch s := suite.(type) {
+		case *SpecSuite:
+			s.capture = true
+		case *FeatureSuite:
+			s.capture = true
+		}
+	}
+}
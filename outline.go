@@ -0,0 +1,260 @@
+package gospec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var outlinePlaceholder = regexp.MustCompile(`<([^<>]*)>`) //nolint:gochecknoglobals
+
+// ScenarioOutline is used to define a scenario which gets executed once per example row.
+// The examples are a table, whereby the first row holds the column names. The callback
+// gets called for each of the example rows, with the row values mapped by the column
+// names. The `<column>` placeholders in the titles of the scenario and its steps get
+// replaced with the respective values.
+//
+// Example:
+//
+//	scenarioOutline("adding <count> items", [][]string{
+//		{"count"},
+//		{"1"},
+//		{"2"},
+//	}, func(row map[string]string) {
+//		when("<count> items are added", func(t *testing.T) {
+//			/* use row["count"] */
+//		})
+//	})
+//
+// The optional tags are the same as the ones of a [Scenario].
+type ScenarioOutline func(title string, examples [][]string, cb func(row map[string]string), tags ...string)
+
+// OutlineAPI returns the [ScenarioOutline] function, which can be used along with the
+// ones returned by [FeatureSuite.API] or [FeatureSuite.ParallelAPI].
+func (fs *FeatureSuite) OutlineAPI() ScenarioOutline {
+	return fs.scenarioOutline
+}
+
+// outline holds the state of the scenario outline being defined.
+type outline struct {
+	node   *node2
+	values map[string]string
+	first  bool
+}
+
+func (fs *FeatureSuite) scenarioOutline(
+	title string,
+	examples [][]string,
+	cb func(row map[string]string),
+	tags ...string,
+) {
+	fs.t.Helper()
+	fs.scenarioOutlineAt(callerLocation(2), title, examples, cb, tags...)
+}
+
+func (fs *FeatureSuite) scenarioOutlineAt(
+	at sourceLocation,
+	title string,
+	examples [][]string,
+	cb func(row map[string]string),
+	tags ...string,
+) {
+	fs.t.Helper()
+	if fs.prevKind() != isFeature && fs.prevKind() != isBackground && fs.prevKind() != isRule {
+		fs.invalid = true
+		fs.t.Errorf("invalid position for `ScenarioOutline` function, it must be inside a `Feature` or `Rule` call")
+		return
+	}
+
+	if len(examples) < 2 {
+		fs.invalid = true
+		fs.t.Errorf("expected the examples to have a header and at least one row")
+		return
+	}
+
+	for _, row := range examples[1:] {
+		if len(row) != len(examples[0]) {
+			fs.invalid = true
+			fs.t.Errorf("expected the examples rows to have %d values but got %d", len(examples[0]), len(row))
+			return
+		}
+	}
+
+	if err := validateTags(tags); err != nil {
+		fs.invalid = true
+		fs.t.Errorf("%s", err)
+		return
+	}
+
+	title, description := splitTitle(title)
+
+	n := &node2{
+		step: &featureStep{
+			kind:        isScenarioOutline,
+			title:       title,
+			description: description,
+			tags:        tags,
+			lineNo:      at.lineNo,
+			file:        at.file,
+		},
+	}
+	fs.currNode.children = append(fs.currNode.children, n)
+
+	examplesNode := &node2{
+		step: &featureStep{
+			kind: isExamples,
+			rows: examples,
+		},
+	}
+
+	for i, row := range examples[1:] {
+		values := make(map[string]string, len(row))
+		for j, name := range examples[0] {
+			values[name] = row[j]
+		}
+
+		fs.outline = &outline{node: n, values: values, first: i == 0}
+
+		s := &featureStep{
+			kind:        isScenario,
+			title:       fs.interpolate(title),
+			description: description,
+			tags:        tags,
+			lineNo:      at.lineNo,
+			file:        at.file,
+		}
+		fs.pushStack(s)
+
+		rowNode := &node2{step: s}
+		examplesNode.children = append(examplesNode.children, rowNode)
+
+		fs.currNode = rowNode
+		fs.pushStack2(rowNode)
+
+		cb(values)
+
+		fs.popStack2(rowNode)
+		fs.currNode = fs.nodesStack[len(fs.nodesStack)-1]
+
+		if len(fs.stack) > 0 {
+			fs.copyStack()
+			fs.popStackUntilStep(s)
+		}
+
+		fs.popStack(s)
+	}
+
+	fs.outline = nil
+
+	n.children = append(n.children, examplesNode)
+}
+
+// interpolate replaces the `<column>` placeholders in the text with the values of the
+// current example row, when inside a scenario outline. The text is replaced in a single
+// pass, so placeholders within the values are left as they are.
+func (fs *FeatureSuite) interpolate(text string) string {
+	if fs.outline == nil {
+		return text
+	}
+	return outlinePlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		if value, ok := fs.outline.values[placeholder[1:len(placeholder)-1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// addOutlineStep adds the step, as defined in the outline (i.e. without the placeholders
+// being replaced), to the outline node, so that the outline can be rendered.
+func (fs *FeatureSuite) addOutlineStep(at sourceLocation, kind featureStepKind, conjunction, title string) {
+	if fs.outline == nil || !fs.outline.first || fs.inBackground {
+		return
+	}
+
+	fs.outline.node.children = append(fs.outline.node.children, &node2{
+		step: &featureStep{
+			kind:        kind,
+			conjunction: conjunction,
+			title:       title,
+			lineNo:      at.lineNo,
+			file:        at.file,
+		},
+	})
+}
+
+// addOutlineArgument adds the data table or the doc string of the step, as defined in the
+// outline, to the outline step added last, so that the outline gets rendered along with it.
+func (fs *FeatureSuite) addOutlineArgument(n *node2) {
+	if fs.outline == nil || !fs.outline.first || fs.inBackground || len(fs.outline.node.children) == 0 {
+		return
+	}
+
+	last := fs.outline.node.children[len(fs.outline.node.children)-1]
+	last.children = append(last.children, n)
+}
+
+// scenarioStatus returns the status of an executed scenario, or an empty string
+// when the scenario has not been executed.
+func scenarioStatus(s *featureStep) string {
+	switch {
+	case s.status == statusUndefined:
+		return statusUndefined
+	case s.t == nil:
+		return ""
+	case s.t.Failed():
+		return statusFailed
+	case s.t.Skipped():
+		return statusSkipped
+	}
+	return statusPassed
+}
+
+// writeExamples writes the examples table, with each row annotated with the
+// status of its scenario.
+func (n *node2) writeExamples(sb *strings.Builder, indent int, output *output1) {
+	sb.WriteString(fmt.Sprintf("\n%s%sExamples:%s\n", strings.Repeat(output.indentStep, indent), boldIf(output), noBoldIf(output)))
+
+	var table strings.Builder
+	writeTable(&table, strings.Repeat(output.indentStep, indent+1), n.step.rows, nil, false)
+
+	lines := strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n")
+	for i, l := range lines {
+		sb.WriteString(l)
+		if i > 0 && i <= len(n.children) {
+			sb.WriteString(exampleIcon(output, scenarioStatus(n.children[i-1].step)))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+func exampleIcon(output *output1, status string) string {
+	icon, color := "", ""
+	switch status {
+	case statusPassed:
+		icon, color = " ✔", green
+	case statusFailed:
+		icon, color = " ⨯", red
+	case statusSkipped:
+		icon, color = " [skip]", cyan
+	case statusUndefined:
+		icon, color = " ?", yellow
+	}
+	if output.colorful && icon != "" {
+		return color + icon + noColor
+	}
+	return icon
+}
+
+func boldIf(output *output1) string {
+	if output.colorful {
+		return bold
+	}
+	return ""
+}
+
+func noBoldIf(output *output1) string {
+	if output.colorful {
+		return noBold
+	}
+	return ""
+}
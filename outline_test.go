@@ -0,0 +1,133 @@
+package gospec
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestScenarioOutline(t *testing.T) {
+	var (
+		out      bytes.Buffer
+		gherkin  bytes.Buffer
+		spec     *FeatureSuite
+		tm       = &mock{t: t}
+		mockCart = &assertMock{}
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			spec = s
+			s.t = tm
+			feature, background, _, given, when, then, _ := s.With(Output(&out), Output(&gherkin, Gherkin)).API()
+			scenarioOutline := s.OutlineAPI()
+
+			feature("Cart", func() {
+				var cart []string
+
+				background(func() {
+					given("an empty cart", func(t *T) {
+						cart = nil
+					})
+				})
+
+				scenarioOutline("adding <count> items", [][]string{
+					{"count", "name"},
+					{"1", "Gopher toy"},
+					{"2", "Crab toy"},
+				}, func(row map[string]string) {
+					when("<count> <name> items are added", func(t *T) {
+						n, _ := strconv.Atoi(row["count"])
+						for i := 0; i < n; i++ {
+							cart = append(cart, row["name"])
+						}
+					})
+					then("the cart has <count> items", func(t *T) {
+						mockCart.Assert(cart)
+					})
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, 2, len(spec.suites))
+	assert.Equal(t, "adding 2 items", spec.suites[1][3].title)
+	assert.Equal(t, "2 Crab toy items are added", spec.suites[1][4].title)
+	assert.Equal(t, "the cart has 2 items", spec.suites[1][5].title)
+	assert.Equal(t, []string{
+		"Cart/adding 1 items",
+		"Cart/adding 2 items",
+	}, tm.testTitles)
+	assert.Equal(t, [][]any{
+		{[]string{"Gopher toy"}},
+		{[]string{"Crab toy", "Crab toy"}},
+	}, mockCart.calls)
+
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Cart`,
+		``,
+		`  Background:`,
+		`    ✔ Given an empty cart`,
+		``,
+		`  Scenario Outline: adding <count> items`,
+		`    When <count> <name> items are added`,
+		`    Then the cart has <count> items`,
+		``,
+		`    Examples:`,
+		`      | count | name       |`,
+		`      | 1     | Gopher toy | ✔`,
+		`      | 2     | Crab toy   | ✔`,
+		``,
+		``,
+	}, "\n"), out.String())
+
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Cart`,
+		``,
+		`  Background:`,
+		`    Given an empty cart`,
+		``,
+		`  Scenario Outline: adding <count> items`,
+		`    When <count> <name> items are added`,
+		`    Then the cart has <count> items`,
+		``,
+		`    Examples:`,
+		`      | count | name       |`,
+		`      | 1     | Gopher toy |`,
+		`      | 2     | Crab toy   |`,
+		``,
+		``,
+	}, "\n"), gherkin.String())
+}
+
+func TestScenarioOutlineExamplesAreValidated(t *testing.T) {
+	tm := &mock{t: t}
+
+	WithFeatureSuite(t, func(s *FeatureSuite) {
+		s.t = tm
+		feature, _, _, _, _, _, _ := s.API()
+		scenarioOutline := s.OutlineAPI()
+
+		feature("Cart", func() {
+			scenarioOutline("no rows", [][]string{{"count"}}, func(map[string]string) {})
+		})
+	})
+
+	assert.Equal(t, [][]any{{"expected the examples to have a header and at least one row"}}, tm.calls)
+}
+
+func TestScenarioOutlinePlaceholdersAreReplacedInASinglePass(t *testing.T) {
+	fs := &FeatureSuite{outline: &outline{values: map[string]string{
+		"from": "<to>",
+		"to":   "<from>",
+		"name": "Gopher toy",
+	}}}
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "moving <to> to <from>, <unknown> Gopher toy", fs.interpolate("moving <from> to <to>, <unknown> <name>"))
+	}
+}
@@ -0,0 +1,229 @@
+package gospec
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	statusPassed  = "passed"
+	statusFailed  = "failed"
+	statusSkipped = "skipped"
+	// statusUndefined is the status of the feature steps which have no step definition,
+	// and of the scenarios which stopped at such a step.
+	statusUndefined = "undefined"
+)
+
+// reportNode is a format agnostic representation of a spec or feature tree
+// node, which is used by the report outputs, e.g. the [HTML] one.
+type reportNode struct {
+	keyword     string
+	title       string
+	description string
+	status      string
+	duration    time.Duration
+	file        string
+	lineNo      int
+	tables      []reportTable
+	docString   *DocString
+	attachments []*reportAttachment
+	// output is the output captured while running a failed `it` block or scenario.
+	output   string
+	leaf     bool
+	children []*reportNode
+}
+
+// reportTable holds the rows of a data table, along with its numeric columns,
+// which get right-aligned.
+type reportTable struct {
+	rows    [][]string
+	numeric []bool
+}
+
+// reportAttachment is an attachment of a report node, along with the link to it, which is a
+// data URI unless the attachment gets saved next to the output file.
+type reportAttachment struct {
+	*attachment
+	href string
+}
+
+func reportAttachments(attachments []*attachment) []*reportAttachment {
+	result := make([]*reportAttachment, 0, len(attachments))
+	for _, a := range attachments {
+		result = append(result, &reportAttachment{attachment: a, href: a.dataURI()})
+	}
+	return result
+}
+
+func (r *reportNode) location() string {
+	if r.file == "" {
+		return ""
+	}
+	return strings.TrimPrefix(r.file, basePath) + ":" + strconv.Itoa(r.lineNo)
+}
+
+// aggregate sets the status of the container nodes based on the statuses
+// of their children, i.e. a container is failed when any of its children
+// failed and skipped when all of them got skipped.
+func (r *reportNode) aggregate() string {
+	if r.leaf {
+		return r.status
+	}
+
+	if len(r.children) == 0 {
+		return r.status
+	}
+
+	skipped, undefined := true, false
+	for _, c := range r.children {
+		switch c.aggregate() {
+		case statusFailed:
+			r.status = statusFailed
+		case statusSkipped:
+		case statusUndefined:
+			skipped, undefined = false, true
+		default:
+			skipped = false
+		}
+	}
+
+	if r.status == statusFailed {
+		return r.status
+	}
+
+	if undefined {
+		r.status = statusUndefined
+		return r.status
+	}
+
+	if skipped {
+		r.status = statusSkipped
+	} else if r.status == "" {
+		r.status = statusPassed
+	}
+
+	return r.status
+}
+
+func (t tree) report(suite *SpecSuite) []*reportNode {
+	nodes := make([]*reportNode, 0, len(t))
+	for _, n := range t {
+		r := n.report(suite)
+		r.aggregate()
+		nodes = append(nodes, r)
+	}
+	return nodes
+}
+
+func (n *node) report(suite *SpecSuite) *reportNode {
+	r := &reportNode{
+		title:  n.step.title,
+		file:   n.step.file,
+		lineNo: n.step.lineNo,
+	}
+
+	if n.step.block == isIt {
+		r.leaf = true
+		r.duration = n.step.timeSpent
+		r.attachments = reportAttachments(n.step.attachments)
+		r.output = n.step.output
+		r.status = statusPassed
+		if n.skipped(suite) {
+			r.status = statusSkipped
+		}
+		if n.failed(suite) {
+			r.status = statusFailed
+		}
+	}
+
+	for _, c := range n.children {
+		r.children = append(r.children, c.report(suite))
+	}
+
+	return r
+}
+
+func (t tree2) report() []*reportNode {
+	nodes := make([]*reportNode, 0, len(t))
+	for _, n := range t {
+		r := n.report()
+		r.aggregate()
+		nodes = append(nodes, r)
+	}
+	return nodes
+}
+
+func (n *node2) report() *reportNode {
+	r := &reportNode{
+		keyword:     n.step.keyword(),
+		title:       n.step.title,
+		description: n.step.description,
+		file:        n.step.file,
+		lineNo:      n.step.lineNo,
+	}
+
+	switch n.step.kind { //nolint:exhaustive
+	case isScenario:
+		r.status = scenarioStatus(n.step)
+		r.duration = n.step.timeSpent
+		r.output = n.step.output
+	case isScenarioOutline:
+		// the outline is reported as the scenarios executed for each of the example rows
+		for _, c := range n.children {
+			if c.step.kind == isExamples {
+				for _, row := range c.children {
+					r.children = append(r.children, row.report())
+				}
+			}
+		}
+		return r
+	case isGiven, isWhen, isThen:
+		r.leaf = true
+		r.status = n.step.status
+		r.duration = n.step.timeSpent
+		r.attachments = reportAttachments(n.step.attachments)
+	}
+
+	for _, c := range n.children {
+		if c.step.kind == isTable {
+			r.tables = append(r.tables, reportTable{rows: c.step.rows, numeric: c.step.numeric})
+			continue
+		}
+		if c.step.kind == isDocString {
+			r.docString = c.step.docString
+			continue
+		}
+		r.children = append(r.children, c.report())
+	}
+
+	return r
+}
+
+func (k featureStepKind) keyword() string {
+	switch k { //nolint:exhaustive
+	case isFeature:
+		return "Feature"
+	case isRule:
+		return "Rule"
+	case isBackground:
+		return "Background"
+	case isScenario:
+		return "Scenario"
+	case isScenarioOutline:
+		return "Scenario Outline"
+	case isExamples:
+		return "Examples"
+	case isGiven:
+		return "Given"
+	case isWhen:
+		return "When"
+	case isThen:
+		return "Then"
+	case isAnd:
+		return "And"
+	case isBut:
+		return "But"
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package gospec
+
+// Rule is used to group the scenarios of a feature which illustrate a single business
+// rule. It must be called inside a [Feature], and can have its own [Background], which
+// runs after the background of the feature. The optional tags are inherited by the
+// scenarios of the rule, as with the ones of a [Feature].
+//
+// Example:
+//
+//	rule("free shipping over $50", func() {
+//		background(func() {
+//			given("a cart worth $60", func(t *testing.T) {})
+//		})
+//
+//		scenario("checking out", func() {
+//			then("the shipping is free", func(t *testing.T) {})
+//		})
+//	})
+type Rule func(title string, cb func(), tags ...string)
+
+// RuleAPI returns the [Rule] function, which can be used along with the ones
+// returned by [FeatureSuite.API] or [FeatureSuite.ParallelAPI].
+func (fs *FeatureSuite) RuleAPI() Rule {
+	return fs.ruleBlock
+}
+
+func (fs *FeatureSuite) ruleBlock(title string, cb func(), tags ...string) {
+	fs.t.Helper()
+	fs.ruleAt(callerLocation(2), title, cb, tags...)
+}
+
+func (fs *FeatureSuite) ruleAt(at sourceLocation, title string, cb func(), tags ...string) {
+	fs.t.Helper()
+	if fs.prevKind() != isFeature {
+		fs.invalid = true
+		fs.t.Errorf("invalid position for `Rule` function, it must be inside a `Feature` call")
+		return
+	}
+
+	if err := validateTags(tags); err != nil {
+		fs.invalid = true
+		fs.t.Errorf("%s", err)
+		return
+	}
+
+	title, description := splitTitle(title)
+
+	s := &featureStep{
+		kind:        isRule,
+		title:       title,
+		description: description,
+		tags:        tags,
+		lineNo:      at.lineNo,
+		file:        at.file,
+	}
+	fs.pushStack(s)
+
+	n := &node2{step: s}
+
+	fs.currNode.children = append(fs.currNode.children, n)
+	fs.currNode = n
+
+	fs.pushStack2(n)
+
+	fs.rule = s
+
+	cb()
+
+	fs.rule = nil
+	fs.ruleBackground = nil
+
+	fs.popStackUntilStep2(n)
+	fs.popStack2(n)
+	fs.currNode = fs.nodesStack[len(fs.nodesStack)-1]
+
+	fs.popStack(s)
+}
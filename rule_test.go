@@ -0,0 +1,149 @@
+package gospec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestRules(t *testing.T) {
+	var (
+		out     bytes.Buffer
+		gherkin bytes.Buffer
+		spec    *FeatureSuite
+		tm      = &mock{t: t}
+		calls   []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			spec = s
+			s.t = tm
+			feature, background, scenario, given, _, then, _ := s.With(Output(&out), Output(&gherkin, Gherkin)).API()
+			rule := s.RuleAPI()
+
+			feature("Shipping", func() {
+				background(func() {
+					given("an empty cart", func(t *T) { calls = append(calls, "feature background") })
+				})
+
+				scenario("checking out an empty cart", func() {
+					then("there is nothing to ship", func(t *T) { calls = append(calls, "scenario 1") })
+				})
+
+				rule("free shipping over $50\nThe shipping is free for carts worth more than $50.", func() {
+					background(func() {
+						given("a cart worth $60", func(t *T) { calls = append(calls, "rule background") })
+					})
+
+					scenario("checking out", func() {
+						then("the shipping is free", func(t *T) { calls = append(calls, "scenario 2") })
+					})
+				}, "@shipping")
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{
+		"feature background",
+		"scenario 1",
+		"feature background",
+		"rule background",
+		"scenario 2",
+	}, calls)
+	assert.Equal(t, []string{
+		"Shipping/checking out an empty cart",
+		"Shipping/free shipping over $50/checking out",
+	}, tm.testTitles)
+	assert.Equal(t, 0, len(spec.stack))
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Shipping`,
+		``,
+		`  Background:`,
+		`    ✔ Given an empty cart`,
+		``,
+		`  ✔ Scenario: checking out an empty cart`,
+		`    ✔ Then there is nothing to ship`,
+		``,
+		`  @shipping`,
+		`  Rule: free shipping over $50`,
+		`    The shipping is free for carts worth more than $50.`,
+		``,
+		`    Background:`,
+		`      ✔ Given a cart worth $60`,
+		``,
+		`    ✔ Scenario: checking out`,
+		`      ✔ Then the shipping is free`,
+		``,
+		``,
+	}, "\n"), out.String())
+	assert.Equal(t, strings.ReplaceAll(out.String(), "✔ ", ""), gherkin.String())
+}
+
+func TestRulesMustBeInsideAFeature(t *testing.T) {
+	tm := &mock{t: t}
+
+	WithFeatureSuite(t, func(s *FeatureSuite) {
+		s.t = tm
+		feature, _, scenario, _, _, _, _ := s.API()
+		rule := s.RuleAPI()
+
+		feature("Shipping", func() {
+			scenario("checking out", func() {
+				rule("free shipping over $50", func() {})
+			})
+		})
+	})
+
+	assert.Equal(t, [][]any{
+		{"invalid position for `Rule` function, it must be inside a `Feature` call"},
+	}, tm.calls)
+}
+
+func TestImportedFeatureWithRules(t *testing.T) {
+	var (
+		out   bytes.Buffer
+		tm    = &mock{t: t}
+		calls []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			s.With(Output(&out))
+
+			s.Step(`^(.+)$`, func(t *T, step string) {
+				calls = append(calls, step)
+			})
+
+			s.ImportFeatureText("shipping.feature", strings.Join([]string{
+				`Feature: Shipping`,
+				`  Rule: free shipping over $50`,
+				`    Background:`,
+				`      Given a cart worth $60`,
+				``,
+				`    Scenario: checking out`,
+				`      Then the shipping is free`,
+			}, "\n"))
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{"a cart worth $60", "the shipping is free"}, calls)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Shipping`,
+		``,
+		`  Rule: free shipping over $50`,
+		``,
+		`    Background:`,
+		`      ✔ Given a cart worth $60`,
+		``,
+		`    ✔ Scenario: checking out`,
+		`      ✔ Then the shipping is free`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
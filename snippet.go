@@ -0,0 +1,103 @@
+package gospec
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// snippetParameter matches the parts of the step titles which get turned into parameters
+// of the snippets, i.e. the quoted texts and the numbers.
+var snippetParameter = regexp.MustCompile(`"[^"]*"|'[^']*'|-?\d+(\.\d+)?`) //nolint:gochecknoglobals
+
+// undefinedStep is a step without a step definition, which gets reported at the end of
+// the suite, see [FeatureSuite.reportSteps].
+type undefinedStep struct {
+	at       sourceLocation
+	kind     featureStepKind
+	title    string
+	argument any
+}
+
+// snippet returns a ready-to-paste step definition for the step, whereby the quoted texts
+// and the numbers of the title are turned into {string}, {int} and {float} parameters, e.g.
+//
+//	s.Define("the cart has {int} items", func(t *testing.T, arg1 int) {
+//		t.Skip("pending")
+//	})
+func (u undefinedStep) snippet(parallel bool) string {
+	var (
+		expression strings.Builder
+		args       = []string{"t *testing.T"}
+		params     int
+		last       int
+	)
+
+	if parallel {
+		args = append(args, "w *gospec.World")
+	}
+
+	param := func(name, typ string) {
+		params++
+		expression.WriteString("{" + name + "}")
+		args = append(args, fmt.Sprintf("arg%d %s", params, typ))
+	}
+
+	for _, m := range snippetParameter.FindAllStringSubmatchIndex(u.title, -1) {
+		start, end := m[0], m[1]
+		if !isSnippetBoundary(u.title, start-1) || !isSnippetBoundary(u.title, end) {
+			continue
+		}
+
+		expression.WriteString(escapeExpression(u.title[last:start]))
+		switch {
+		case u.title[start] == '"' || u.title[start] == '\'':
+			param("string", "string")
+		case m[2] >= 0:
+			param("float", "float64")
+		default:
+			param("int", "int")
+		}
+		last = end
+	}
+	expression.WriteString(escapeExpression(u.title[last:]))
+
+	switch u.argument.(type) {
+	case DataTable:
+		args = append(args, "table gospec.DataTable")
+	case DocString:
+		args = append(args, "doc gospec.DocString")
+	}
+
+	quoted := strconv.Quote(expression.String())
+	if strings.Contains(expression.String(), `\`) && !strings.Contains(expression.String(), "`") {
+		quoted = "`" + expression.String() + "`"
+	}
+
+	return fmt.Sprintf("s.Define(%s, func(%s) {\n\tt.Skip(\"pending\")\n})\n", quoted, strings.Join(args, ", "))
+}
+
+// isSnippetBoundary reports whether the character at the index doesn't belong to the same
+// word as a parameter next to it, e.g. the "2" in "md5" is not a parameter.
+func isSnippetBoundary(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return true
+	}
+	r := rune(s[i])
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+}
+
+// escapeExpression escapes the characters which have a special meaning in the Cucumber
+// expressions, see [compileExpression].
+func escapeExpression(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`{`, `\{`,
+		`}`, `\}`,
+		`(`, `\(`,
+		`)`, `\)`,
+		`/`, `\/`,
+	).Replace(s)
+}
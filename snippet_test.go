@@ -0,0 +1,91 @@
+package gospec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestSnippets(t *testing.T) {
+	for _, tc := range []struct {
+		step     undefinedStep
+		parallel bool
+		expected string
+	}{
+		{
+			step: undefinedStep{title: `the cart has 2 items worth 14.99 named "Gopher toy"`},
+			expected: "s.Define(\"the cart has {int} items worth {float} named {string}\", func(t *testing.T, arg1 int, arg2 float64, arg3 string) {\n" +
+				"\tt.Skip(\"pending\")\n" +
+				"})\n",
+		},
+		{
+			step:     undefinedStep{title: "the md5 sum (hex) is/was ok", argument: DocString{}},
+			parallel: true,
+			expected: "s.Define(`the md5 sum \\(hex\\) is\\/was ok`, func(t *testing.T, w *gospec.World, doc gospec.DocString) {\n" +
+				"\tt.Skip(\"pending\")\n" +
+				"})\n",
+		},
+		{
+			step: undefinedStep{title: "the following products", argument: DataTable{}},
+			expected: "s.Define(\"the following products\", func(t *testing.T, table gospec.DataTable) {\n" +
+				"\tt.Skip(\"pending\")\n" +
+				"})\n",
+		},
+	} {
+		assert.Equal(t, tc.expected, tc.step.snippet(tc.parallel))
+	}
+}
+
+func TestUndefinedStepsAreMarkedAsUndefined(t *testing.T) {
+	var (
+		out    bytes.Buffer
+		stderr bytes.Buffer
+		tm     = &mock{t: t}
+		calls  []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t, s.stderr = tm, &stderr
+			s.With(Output(&out))
+
+			s.Define("an empty cart", func(t *T) { calls = append(calls, "given") })
+			s.Define("the cart has {int} items", func(t *T, n int) { calls = append(calls, "then") })
+
+			s.ImportFeatureText("cart.feature", strings.Join([]string{
+				`Feature: Cart`,
+				`  Scenario: adding items`,
+				`    Given an empty cart`,
+				`    When 2 items are added`,
+				`    Then the cart has 2 items`,
+			}, "\n"))
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{"given"}, calls)
+	assert.Equal(t, [][]any(nil), tm.logs)
+	assert.Equal(t, strings.Join([]string{
+		"undefined steps:",
+		"\tWhen 2 items are added (cart.feature:4)",
+		"",
+		"they can be implemented via the following snippets:",
+		"",
+		"s.Define(\"{int} items are added\", func(t *testing.T, arg1 int) {",
+		"\tt.Skip(\"pending\")",
+		"})",
+		"",
+	}, "\n"), stderr.String())
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Cart`,
+		``,
+		`  ? Scenario: adding items`,
+		`    ✔ Given an empty cart`,
+		`    ? When 2 items are added`,
+		`    - Then the cart has 2 items`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
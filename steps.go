@@ -0,0 +1,508 @@
+package gospec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+//nolint:gochecknoglobals
+var (
+	testingTType  = reflect.TypeOf((*testing.T)(nil))
+	worldType     = reflect.TypeOf((*World)(nil))
+	tableRowsType = reflect.TypeOf([][]string(nil))
+	stringType    = reflect.TypeOf("")
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+
+	errUndefinedStep = errors.New("undefined step")
+)
+
+// stepDefinition is a step implementation which is matched against the step
+// titles by a pattern, with the pattern captures passed as the step arguments.
+type stepDefinition struct {
+	// expression is the pattern, or the Cucumber expression, the step was defined with.
+	expression string
+	pattern    *regexp.Regexp
+	// params holds the parameter types of a step defined by a Cucumber expression, and
+	// groups the indexes of their pattern groups.
+	params   []*parameterType
+	groups   []int
+	fn       reflect.Value
+	parallel bool
+	at       sourceLocation
+}
+
+type stepRegistry struct {
+	definitions    []*stepDefinition
+	parameterTypes map[string]*parameterType
+}
+
+func (r *stepRegistry) define(at sourceLocation, pattern string, fn any) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid step pattern %q: %w", pattern, err)
+	}
+
+	d, err := newStepDefinition(at, pattern, fn)
+	if err != nil {
+		return err
+	}
+	d.pattern = re
+
+	r.definitions = append(r.definitions, d)
+
+	return nil
+}
+
+// defineExpression defines a step by a Cucumber expression, whereby the types of the
+// function arguments get checked against the parameter types of the expression.
+func (r *stepRegistry) defineExpression(at sourceLocation, expression string, fn any) error {
+	d, err := newStepDefinition(at, expression, fn)
+	if err != nil {
+		return err
+	}
+
+	d.pattern, d.params, d.groups, err = compileExpression(expression, r.parameterTypes)
+	if err != nil {
+		return err
+	}
+
+	typ := d.fn.Type()
+	offset := 1
+	if d.parallel {
+		offset++
+	}
+
+	// the data table or the doc string of the step can follow the parameters
+	if n := typ.NumIn() - offset; n != len(d.params) && n != len(d.params)+1 {
+		return fmt.Errorf("step definition %q has %d parameters but the function takes %d arguments after the *testing.T", expression, len(d.params), typ.NumIn()-1)
+	}
+
+	for i, p := range d.params {
+		if argType := typ.In(offset + i); !p.accepts(argType) {
+			return fmt.Errorf("step definition %q: can not use {%s} as %v", expression, p.name, argType)
+		}
+	}
+
+	r.definitions = append(r.definitions, d)
+
+	return nil
+}
+
+func newStepDefinition(at sourceLocation, expression string, fn any) (*stepDefinition, error) {
+	f := reflect.ValueOf(fn)
+	if f.Kind() != reflect.Func {
+		return nil, fmt.Errorf("expected the step definition for %q to be a function but was of type: %v", expression, reflect.TypeOf(fn))
+	}
+
+	typ := f.Type()
+	if typ.NumIn() == 0 || typ.In(0) != testingTType || typ.NumOut() != 0 {
+		return nil, fmt.Errorf("expected the step definition for %q to be a function with *testing.T as first argument and no return values", expression)
+	}
+
+	return &stepDefinition{
+		expression: expression,
+		fn:         f,
+		parallel:   typ.NumIn() > 1 && typ.In(1) == worldType,
+		at:         at,
+	}, nil
+}
+
+// match returns the step definition matching the title, along with the captured arguments.
+func (r *stepRegistry) match(title string) (*stepDefinition, []string, error) {
+	var (
+		found    *stepDefinition
+		captures []string
+	)
+
+	for _, d := range r.definitions {
+		m := d.pattern.FindStringSubmatch(title)
+		if m == nil {
+			continue
+		}
+		if found != nil {
+			return nil, nil, fmt.Errorf("ambiguous step %q, matched by both %q and %q", title, found.expression, d.expression)
+		}
+		found, captures = d, m[1:]
+		if d.groups != nil {
+			captures = make([]string, 0, len(d.groups))
+			for _, g := range d.groups {
+				captures = append(captures, m[g])
+			}
+		}
+	}
+
+	if found == nil {
+		return nil, nil, fmt.Errorf("%w %q", errUndefinedStep, title)
+	}
+
+	return found, captures, nil
+}
+
+// call invokes the step definition, converting the captured arguments into the types of
+// the function arguments. The argument, when not nil, is the data table or doc string of
+// the step and is passed as the last argument.
+func (d *stepDefinition) call(t *testing.T, w *World, captures []string, argument any) error {
+	typ := d.fn.Type()
+
+	args := []reflect.Value{reflect.ValueOf(t)}
+	if d.parallel {
+		args = append(args, reflect.ValueOf(w))
+	}
+
+	expected := len(args) + len(captures)
+	if argument != nil {
+		expected++
+	}
+
+	if typ.NumIn() != expected {
+		return fmt.Errorf("step definition %q expects %d arguments but was given %d", d.expression, typ.NumIn(), expected)
+	}
+
+	for i, c := range captures {
+		convert := convertArgument
+		if d.params != nil {
+			convert = d.params[i].convert
+		}
+		v, err := convert(c, typ.In(len(args)))
+		if err != nil {
+			return fmt.Errorf("step definition %q: %w", d.expression, err)
+		}
+		args = append(args, v)
+	}
+
+	if argument != nil {
+		v := reflect.ValueOf(argument)
+		if dt, ok := argument.(DataTable); ok && typ.In(len(args)) == tableRowsType {
+			v = reflect.ValueOf(dt.rows)
+		}
+		if doc, ok := argument.(DocString); ok && typ.In(len(args)) == stringType {
+			v = reflect.ValueOf(doc.Content)
+		}
+		if !v.Type().AssignableTo(typ.In(len(args))) {
+			return fmt.Errorf("step definition %q: can not use %v as %v", d.expression, v.Type(), typ.In(len(args)))
+		}
+		args = append(args, v)
+	}
+
+	d.fn.Call(args)
+
+	return nil
+}
+
+func convertArgument(s string, typ reflect.Type) (reflect.Value, error) {
+	v := reflect.New(typ).Elem()
+
+	switch typ.Kind() { //nolint:exhaustive
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, typ.Bits())
+		if err != nil {
+			return v, fmt.Errorf("can not convert %q to %v", s, typ)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, typ.Bits())
+		if err != nil {
+			return v, fmt.Errorf("can not convert %q to %v", s, typ)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, typ.Bits())
+		if err != nil {
+			return v, fmt.Errorf("can not convert %q to %v", s, typ)
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return v, fmt.Errorf("can not convert %q to %v", s, typ)
+		}
+		v.SetBool(b)
+	default:
+		return v, fmt.Errorf("unsupported argument type %v", typ)
+	}
+
+	return v, nil
+}
+
+// Step defines the implementation of the steps in the imported Gherkin features (see
+// [FeatureSuite.ImportFeature]). The pattern is a regular expression which gets matched
+// against the step titles, whereby the captured groups get passed to the function as arguments.
+//
+// The function must accept a *[testing.T] as first argument, followed by a *[World] when
+// the suite is used via [FeatureSuite.ParallelAPI]. Then come the captured arguments, which
+// get converted to the argument types (string, bool, ints, uints and floats are supported).
+// Steps with a data table or a doc string take it as an additional last argument, of
+// type [DataTable] (or [][]string) and [DocString] (or string) respectively.
+//
+// Example:
+//
+//	s.Step(`^there are (\d+) items in the cart$`, func(t *testing.T, n int) {
+//		/* set up a cart with n items */
+//	})
+func (fs *FeatureSuite) Step(pattern string, fn any) {
+	fs.t.Helper()
+	if err := fs.steps.define(callerLocation(2), pattern, fn); err != nil {
+		fs.t.Errorf("%s", err)
+	}
+}
+
+// Define defines the implementation of the steps whose titles match the Cucumber expression.
+// The expression is the step title, which can hold parameters that get passed to the
+// function as arguments, e.g.
+//
+//	s.Define("the cart has {int} item(s)", func(t *testing.T, n int) {
+//		/* assert that the cart has n items */
+//	})
+//
+// The available parameter types are {int}, {float}, {word}, {string} (a text in single or
+// double quotes), {} (any text) and the custom ones defined via [FeatureSuite.ParameterType].
+// The expression can also hold optional text, e.g. "item(s)", and alternative text, e.g.
+// "is/are", and the special characters are escaped by a backslash. The types of the
+// function arguments get checked against the parameter types when the step is defined.
+// The function has the same form as the ones passed to [FeatureSuite.Step].
+//
+// The defined steps are used by the imported features, and by the steps added via [Given.Use].
+func (fs *FeatureSuite) Define(expression string, fn any) {
+	fs.t.Helper()
+	if err := fs.steps.defineExpression(callerLocation(2), expression, fn); err != nil {
+		fs.t.Errorf("%s", err)
+	}
+}
+
+// ParameterType defines a custom parameter type for the Cucumber expressions of the steps
+// defined after it via [FeatureSuite.Define]. The pattern is a regular expression matching
+// the parameter text, and the transformer is either a func(string) T or a
+// func(string) (T, error), converting the text to the value passed to the steps, e.g.
+//
+//	s.ParameterType("color", `red|green|blue`, func(s string) Color {
+//		return Color(s)
+//	})
+//	s.Define("a {color} ball", func(t *testing.T, c Color) {
+//		/* ... */
+//	})
+func (fs *FeatureSuite) ParameterType(name, pattern string, transformer any) {
+	fs.t.Helper()
+	if err := fs.steps.defineParameterType(name, pattern, transformer); err != nil {
+		fs.t.Errorf("%s", err)
+	}
+}
+
+// ImportFeature reads a Gherkin `.feature` file and runs it via the [FeatureSuite], with the
+// steps implemented by the step definitions (see [FeatureSuite.Step]). Each scenario runs as
+// a separate subtest, just like the ones defined via the [FeatureSuite.API].
+func (fs *FeatureSuite) ImportFeature(path string) {
+	fs.t.Helper()
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		fs.t.Errorf("failed to read feature file: %s", err)
+		return
+	}
+
+	fs.ImportFeatureText(path, string(src))
+}
+
+// ImportFeatureText is the same as [FeatureSuite.ImportFeature] but takes the Gherkin source
+// directly. The name is used for the source locations of the features and steps.
+func (fs *FeatureSuite) ImportFeatureText(name, src string) {
+	fs.t.Helper()
+
+	if len(fs.outputs) == 0 {
+		fs.outputs = append(fs.outputs, defaultOutput())
+	}
+
+	f, err := parseGherkin(name, src)
+	if err != nil {
+		fs.t.Errorf("%s", err)
+		return
+	}
+
+	file, err := filepath.Abs(name)
+	if err != nil {
+		file = name
+	}
+	at := func(lineNo int) sourceLocation {
+		return sourceLocation{file: file, lineNo: lineNo}
+	}
+
+	fs.featureAt(at(f.lineNo), joinTitle(f.title, f.description), func() {
+		fs.importScenarios(at, f.background, f.scenarios)
+
+		for _, r := range f.rules {
+			r := r
+			fs.ruleAt(at(r.lineNo), joinTitle(r.title, r.description), func() {
+				fs.importScenarios(at, r.background, r.scenarios)
+			}, r.tags...)
+		}
+	}, f.tags...)
+}
+
+func (fs *FeatureSuite) importScenarios(
+	at func(int) sourceLocation,
+	background *gherkinBackground,
+	scenarios []*gherkinScenario,
+) {
+	fs.t.Helper()
+
+	if background != nil {
+		fs.backgroundAt(at(background.lineNo), func() {
+			fs.importSteps(at, background.steps)
+		})
+	}
+
+	for _, sc := range scenarios {
+		sc := sc
+		if sc.outline {
+			for _, examples := range sc.examples {
+				tags := append(append([]string{}, sc.tags...), examples.tags...)
+				fs.scenarioOutlineAt(at(sc.lineNo), joinTitle(sc.title, sc.description), examples.rows, func(map[string]string) {
+					fs.importSteps(at, sc.steps)
+				}, tags...)
+			}
+			continue
+		}
+		fs.scenarioAt(at(sc.lineNo), joinTitle(sc.title, sc.description), func() {
+			fs.importSteps(at, sc.steps)
+		}, sc.tags...)
+	}
+}
+
+// joinTitle joins the title and description into a multi-line title, as it would
+// have been defined when using the [FeatureSuite.API].
+func joinTitle(title, description string) string {
+	if description == "" {
+		return title
+	}
+	return title + "\n" + description
+}
+
+func (fs *FeatureSuite) importSteps(at func(int) sourceLocation, steps []*gherkinStep) {
+	fs.t.Helper()
+
+	kind := isGiven
+
+	for _, gs := range steps {
+		switch gs.keyword {
+		case "Given":
+			kind = isGiven
+		case "When":
+			kind = isWhen
+		case "Then":
+			kind = isThen
+		}
+
+		// the `*` steps inherit the kind of the preceding step without the conjunction
+		stepKind := kind
+		switch gs.keyword {
+		case "And":
+			stepKind = isAnd
+		case "But":
+			stepKind = isBut
+		}
+
+		var (
+			argument any
+			table    [][]string
+		)
+		if gs.table != nil {
+			table = make([][]string, 0, len(gs.table))
+			for _, row := range gs.table {
+				cells := make([]string, 0, len(row))
+				for _, cell := range row {
+					cells = append(cells, fs.interpolate(cell))
+				}
+				table = append(table, cells)
+			}
+			argument = DataTable{rows: table, at: at(gs.lineNo)}
+		}
+		var doc *DocString
+		if gs.docString != nil {
+			doc = &DocString{
+				ContentType: gs.docString.contentType,
+				Content:     fs.interpolate(gs.docString.content),
+			}
+			argument = *doc
+		}
+
+		s := fs.importStep(at(gs.lineNo), stepKind, gs.text, argument)
+
+		if table != nil {
+			s.n.children = append(s.n.children, &node2{
+				step: &featureStep{kind: isTable, rows: table},
+			})
+			fs.addOutlineArgument(&node2{
+				step: &featureStep{kind: isTable, rows: gs.table},
+			})
+		}
+
+		if doc != nil {
+			s.n.children = append(s.n.children, &node2{
+				step: &featureStep{kind: isDocString, docString: doc},
+			})
+			fs.addOutlineArgument(&node2{
+				step: &featureStep{kind: isDocString, docString: &DocString{
+					ContentType: gs.docString.contentType,
+					Content:     gs.docString.content,
+				}},
+			})
+		}
+	}
+}
+
+func (fs *FeatureSuite) importStep(at sourceLocation, kind featureStepKind, title string, argument any) *featureStep {
+	fs.t.Helper()
+
+	location := fmt.Sprintf("%s:%d", strings.TrimPrefix(at.file, basePath), at.lineNo)
+
+	d, captures, err := fs.matchStep(at, kind, fs.interpolate(title), argument)
+	if err == nil && d.parallel != fs.parallel {
+		err = fmt.Errorf("step definition %q does not match the suite mode, the *World argument is expected only in parallel suites", d.expression)
+	}
+
+	// an undefined step stops the scenario, which fails only in the strict mode
+	undefined := errors.Is(err, errUndefinedStep)
+	report := func(t *testing.T, err error) {
+		t.Helper()
+		if undefined && !fs.strict {
+			t.Skipf("%s: %s", location, err)
+		}
+		t.Errorf("%s: %s", location, err)
+	}
+
+	var s *featureStep
+	if fs.parallel {
+		s = fs.addStep(at, kind, title, nil, func(t *testing.T, w *World) {
+			t.Helper()
+			callErr := err
+			if callErr == nil {
+				callErr = d.call(t, w, captures, argument)
+			}
+			if callErr != nil {
+				report(t, callErr)
+			}
+		})
+	} else {
+		s = fs.addStep(at, kind, title, func(t *testing.T) {
+			t.Helper()
+			callErr := err
+			if callErr == nil {
+				callErr = d.call(t, nil, captures, argument)
+			}
+			if callErr != nil {
+				report(t, callErr)
+			}
+		}, nil)
+	}
+	s.undefined = undefined
+
+	return s
+}
@@ -0,0 +1,164 @@
+package gospec
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestImportedFeatureRunsWithStepDefinitions(t *testing.T) {
+	var (
+		out  bytes.Buffer
+		tm   = &mock{t: t}
+		cart []string
+		note string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			s.With(Output(&out, PrintFilenames))
+
+			s.Step(`^an empty cart$`, func(t *T) {
+				cart = nil
+			})
+			s.Step(`^(\d+) "([^"]*)" items are added$`, func(t *T, n int, name string) {
+				for i := 0; i < n; i++ {
+					cart = append(cart, name)
+				}
+			})
+			s.Step(`^the following items are added:$`, func(t *T, table [][]string) {
+				for _, row := range table[1:] {
+					n, _ := strconv.Atoi(row[1])
+					for i := 0; i < n; i++ {
+						cart = append(cart, row[0])
+					}
+				}
+			})
+			s.Step(`^the cart has (\d+) items$`, func(t *T, n int) {
+				assert.Equal(t, n, len(cart))
+			})
+			s.Step(`^a note:$`, func(t *T, docString string) {
+				note = docString
+			})
+			s.Step(`^the note has (\d+) lines$`, func(t *T, n uint) {
+				assert.Equal(t, int(n), len(strings.Split(note, "\n")))
+			})
+
+			s.ImportFeature("testdata/cart.feature")
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{
+		"Cart/adding items",
+		"Cart/adding a note",
+	}, tm.testTitles)
+	assert.Equal(t, "Please wrap\n  as a gift", note)
+	assert.Equal(t, strings.Join([]string{
+		`@cart`,
+		`Feature: Cart	testdata/cart.feature:2`,
+		`  As a shopper`,
+		`  I want to keep items in a cart`,
+		``,
+		`  Background:	testdata/cart.feature:6`,
+		`    ✔ Given an empty cart	testdata/cart.feature:7`,
+		``,
+		`  ✔ Scenario: adding items	testdata/cart.feature:10`,
+		`    ✔ When 2 "Gopher toy" items are added	testdata/cart.feature:11`,
+		`    ✔ And the following items are added:	testdata/cart.feature:12`,
+		`      | Name     | Quantity |`,
+		`      | Crab toy | 1        |`,
+		`    ✔ Then the cart has 3 items	testdata/cart.feature:15`,
+		``,
+		`  @wip`,
+		`  ✔ Scenario: adding a note	testdata/cart.feature:18`,
+		`    ✔ Given a note:	testdata/cart.feature:19`,
+		`      """text`,
+		`      Please wrap`,
+		`        as a gift`,
+		`      """`,
+		`    ✔ Then the note has 2 lines	testdata/cart.feature:24`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestImportedOutlinesRenderTheirStepArguments(t *testing.T) {
+	var (
+		gherkin bytes.Buffer
+		tm      = &mock{t: t}
+		names   []string
+		notes   []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			s.With(Output(&gherkin, Gherkin))
+
+			s.Step(`^the following items are added:$`, func(t *T, table [][]string) {
+				names = append(names, table[1][0])
+			})
+			s.Step(`^a note:$`, func(t *T, docString string) {
+				notes = append(notes, docString)
+			})
+
+			s.ImportFeatureText("cart.feature", strings.Join([]string{
+				`Feature: Cart`,
+				``,
+				`  Scenario Outline: adding items`,
+				`    When the following items are added:`,
+				`      | Name   |`,
+				`      | <name> |`,
+				`    And a note:`,
+				`      """`,
+				`      Wrap the <name>`,
+				`      """`,
+				``,
+				`    Examples:`,
+				`      | name       |`,
+				`      | Gopher toy |`,
+			}, "\n"))
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{"Gopher toy"}, names)
+	assert.Equal(t, []string{"Wrap the Gopher toy"}, notes)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Cart`,
+		``,
+		`  Scenario Outline: adding items`,
+		`    When the following items are added:`,
+		`      | Name   |`,
+		`      | <name> |`,
+		`    And a note:`,
+		`      """`,
+		`      Wrap the <name>`,
+		`      """`,
+		``,
+		`    Examples:`,
+		`      | name       |`,
+		`      | Gopher toy |`,
+		``,
+		``,
+	}, "\n"), gherkin.String())
+}
+
+func TestStepDefinitionsAreValidated(t *testing.T) {
+	tm := &mock{t: t}
+
+	WithFeatureSuite(t, func(s *FeatureSuite) {
+		s.t = tm
+		s.Step(`^(an invalid pattern$`, func(t *T) {})
+		s.Step(`^not a function$`, "func")
+		s.Step(`^without testing.T$`, func(n int) {})
+	})
+
+	assert.Equal(t, 3, len(tm.calls))
+	assert.Equal(t, "invalid step pattern \"^(an invalid pattern$\": error parsing regexp: missing closing ): `^(an invalid pattern$`", tm.calls[0][1].(error).Error())
+}
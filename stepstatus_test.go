@@ -0,0 +1,244 @@
+package gospec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestRemainingStepsAreSkippedWhenAStepStopsTheScenario(t *testing.T) {
+	var (
+		out   bytes.Buffer
+		tm    = &mock{t: t}
+		calls []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, given, when, then, _ := s.With(Output(&out)).API()
+
+			feature("Checkout", func() {
+				scenario("paying by voucher", func() {
+					given("a voucher", func(t *T) { calls = append(calls, "given") })
+					when("the user pays", func(t *T) {
+						calls = append(calls, "when")
+						t.SkipNow()
+					})
+					then("the order is placed", func(t *T) { calls = append(calls, "then") })
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{"given", "when"}, calls)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Checkout`,
+		``,
+		`  - Scenario: paying by voucher`,
+		`    ✔ Given a voucher`,
+		`    - When the user pays`,
+		`    - Then the order is placed`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestFailedAndSkippedStepsAreMarked(t *testing.T) {
+	step := func(kind featureStepKind, title, status string) *node2 {
+		return &node2{step: &featureStep{kind: kind, title: title, status: status}}
+	}
+
+	tree := tree2{{
+		step: &featureStep{kind: isFeature, title: "Checkout"},
+		children: []*node2{{
+			step: &featureStep{kind: isScenario, title: "paying by card"},
+			children: []*node2{
+				step(isGiven, "a card", statusPassed),
+				step(isWhen, "the user pays", statusFailed),
+				step(isThen, "the order is placed", statusSkipped),
+			},
+		}},
+	}}
+
+	plain := setOutput(&mock{t: t}, &bytes.Buffer{})
+	colorful := setOutput(&mock{t: t}, &bytes.Buffer{}, Colorful)
+	markdown := setOutput(&mock{t: t}, &bytes.Buffer{}, Markdown)
+
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Checkout`,
+		``,
+		`  Scenario: paying by card`,
+		`    ✔ Given a card`,
+		`    ⨯ When the user pays`,
+		`    - Then the order is placed`,
+		``,
+		``,
+	}, "\n"), tree.String(&plain))
+
+	assert.Equal(t, strings.Join([]string{
+		"    \x1b[0;31m⨯\x1b[0m \x1b[0;32mWhen\x1b[0m the user pays",
+		"    \x1b[1;30m- Then the order is placed\x1b[0m",
+	}, "\n"), strings.Join(strings.Split(tree.String(&colorful), "\n")[4:6], "\n"))
+
+	assert.Equal(t, strings.Join([]string{
+		`# Feature: Checkout`,
+		``,
+		`## Scenario: paying by card`,
+		``,
+		`- **Given** a card`,
+		`- **When** the user pays (failed)`,
+		`- ~~**Then** the order is placed~~ (skipped)`,
+		``,
+	}, "\n"), tree.markdown(&markdown))
+}
+
+func TestFeatureOutputWithDurations(t *testing.T) {
+	var (
+		out bytes.Buffer
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, background, scenario, given, when, _, _ := s.With(Output(&out, Durations)).API()
+
+			feature("Checkout", func() {
+				background(func() {
+					given("an empty cart", func(t *T) {})
+				})
+				scenario("paying by card", func() {
+					when("the user pays", func(t *T) {})
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Checkout`,
+		``,
+		`  Background:`,
+		`    ✔ Given an empty cart (0ms)`,
+		``,
+		`  ✔ Scenario: paying by card (0ms)`,
+		`    ✔ When the user pays (0ms)`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestStepsRunAsSubtests(t *testing.T) {
+	var (
+		out   bytes.Buffer
+		tm    = &mock{t: t}
+		names []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, given, when, then, _ := s.With(Output(&out), StepSubtests()).API()
+			and, _ := s.AndButAPI()
+
+			feature("Checkout", func() {
+				scenario("paying by voucher", func() {
+					given("a voucher", func(t *T) { names = append(names, t.Name()) })
+					and("a full cart", func(t *T) { names = append(names, t.Name()) })
+					when("the user pays", func(t *T) {
+						names = append(names, t.Name())
+						t.SkipNow()
+					})
+					then("the order is placed", func(t *T) { names = append(names, t.Name()) })
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{
+		"TestStepsRunAsSubtests/Checkout/paying_by_voucher/Given_a_voucher",
+		"TestStepsRunAsSubtests/Checkout/paying_by_voucher/And_a_full_cart",
+		"TestStepsRunAsSubtests/Checkout/paying_by_voucher/When_the_user_pays",
+	}, names)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Checkout`,
+		``,
+		`  - Scenario: paying by voucher`,
+		`    ✔ Given a voucher`,
+		`    ✔ And a full cart`,
+		`    - When the user pays`,
+		`    - Then the order is placed`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestBackgroundStepsAreRecordedPerScenario(t *testing.T) {
+	var (
+		out       bytes.Buffer
+		tm        = &mock{t: t}
+		nodes     []*node2
+		scenarios int
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, background, scenario, given, when, _, _ := s.With(Output(&out)).API()
+
+			feature("Checkout", func() {
+				background(func() {
+					given("an empty cart", func(t *T) {
+						scenarios++
+						if scenarios == 1 {
+							t.SkipNow()
+						}
+					})
+				})
+				scenario("paying by card", func() {
+					when("the user pays by card", func(t *T) {})
+				})
+				scenario("paying by voucher", func() {
+					when("the user pays by voucher", func(t *T) {})
+				})
+			})
+
+			nodes = s.nodes
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+
+	background := nodes[0].children[0].children[0].step
+	card := nodes[0].children[1].step
+	voucher := nodes[0].children[2].step
+
+	assert.Equal(t, statusSkipped, card.runOf(background).status)
+	assert.Equal(t, statusPassed, voucher.runOf(background).status)
+	assert.Equal(t, statusPassed, background.status)
+}
+
+func TestBackgroundStepReportsASingleRun(t *testing.T) {
+	var (
+		fs         = &FeatureSuite{}
+		background = &featureStep{kind: isGiven, title: "an empty cart"}
+		card       = &featureStep{kind: isScenario, title: "paying by card"}
+		voucher    = &featureStep{kind: isScenario, title: "paying by voucher"}
+		attached   = []*attachment{{name: "cart.json"}}
+	)
+
+	fs.setStepStatus(card, background, &featureStepRun{status: statusPassed, timeSpent: time.Millisecond})
+	fs.setStepStatus(voucher, background, &featureStepRun{status: statusFailed, timeSpent: 2 * time.Millisecond, attachments: attached})
+
+	assert.Equal(t, &featureStepRun{status: statusPassed, timeSpent: time.Millisecond}, card.runOf(background))
+	assert.Equal(t, statusFailed, voucher.runOf(background).status)
+	assert.Equal(t, statusFailed, background.status)
+	assert.Equal(t, 2*time.Millisecond, background.timeSpent)
+	assert.Equal(t, attached, background.attachments)
+}
@@ -0,0 +1,584 @@
+package gospec
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem() //nolint:gochecknoglobals
+
+// TableOption is an option controlling how the values of a table get displayed, which is
+// passed to [TableItems]. The available options are: [ColumnFormat], [ColumnFormatter]
+// and [MaxWidth].
+type TableOption func(options *tableOptions)
+
+type tableOptions struct {
+	formatters map[string]func(value any) string
+	maxWidths  map[string]int
+	maxWidth   int
+}
+
+type tableItems struct {
+	items   any
+	options []TableOption
+}
+
+// TableItems wraps the items passed to the [Table] function, or to the [World.Table]
+// method, along with options for displaying them, e.g.
+//
+//	table(gospec.TableItems(products,
+//		gospec.ColumnFormat("Price", "%.4f"),
+//		gospec.MaxWidth(20, "Description"),
+//	), "Name", "Price", "Description")
+func TableItems(items any, options ...TableOption) any {
+	return tableItems{items: items, options: options}
+}
+
+// ColumnFormat is a [TableOption] which formats the values of a column with the given
+// [fmt] format, e.g. "%.4f" for displaying prices with a precision of four digits.
+func ColumnFormat(column, format string) TableOption {
+	return ColumnFormatter(column, func(value any) string {
+		return fmt.Sprintf(format, value)
+	})
+}
+
+// ColumnFormatter is a [TableOption] which formats the values of a column with the given
+// function. The function gets passed the value of the field, or of the map entry, with the
+// pointers dereferenced, and it's not called for nil values, which are displayed as empty.
+func ColumnFormatter(column string, formatter func(value any) string) TableOption {
+	return func(options *tableOptions) {
+		if options.formatters == nil {
+			options.formatters = map[string]func(any) string{}
+		}
+		options.formatters[column] = formatter
+	}
+}
+
+// MaxWidth is a [TableOption] which truncates the values longer than the given width, with
+// an ellipsis. It applies to the given columns, or to all of them, when none are passed.
+func MaxWidth(width int, columns ...string) TableOption {
+	return func(options *tableOptions) {
+		if len(columns) == 0 {
+			options.maxWidth = width
+			return
+		}
+		if options.maxWidths == nil {
+			options.maxWidths = map[string]int{}
+		}
+		for _, c := range columns {
+			options.maxWidths[c] = width
+		}
+	}
+}
+
+// cell returns the text of a table cell in the given column, and whether the value is a number.
+func (o *tableOptions) cell(column string, v reflect.Value) (string, bool) {
+	value, isNumber := formatTableValue(v)
+
+	if formatter, ok := o.formatters[column]; ok {
+		if v = indirect(v); v.IsValid() && v.CanInterface() &&
+			!((v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil()) {
+			value = formatter(v.Interface())
+		}
+	}
+
+	width, ok := o.maxWidths[column]
+	if !ok {
+		width = o.maxWidth
+	}
+
+	return truncate(value, width), isNumber
+}
+
+// truncate shortens the text to the given display width, ending it with an ellipsis. A
+// width of zero, or less, leaves the text as is.
+func truncate(s string, width int) string {
+	if width <= 0 || displayWidth(s) <= width {
+		return s
+	}
+
+	var sb strings.Builder
+	used := 0
+	for _, r := range s {
+		w := displayWidth(string(r))
+		if used+w > width-1 {
+			break
+		}
+		sb.WriteRune(r)
+		used += w
+	}
+	sb.WriteString("…")
+
+	return sb.String()
+}
+
+// buildTable turns the items into table rows. The items can be a slice of structs or of
+// maps, in which case the first row is the header, holding the column names, and the
+// rest hold the formatted values of the respective struct fields or map entries. The
+// columns are matched against the `gospec:"Header"` tags of the fields, or the field
+// names when there is no such tag, and a nested struct field can be selected by its
+// path, e.g. "Address.City". When no columns are passed, all of the exported fields, or
+// all of the map keys, are used. The items can also be a [][]string, whose first row is
+// the header, or a single struct or map, which is laid out vertically, with a row per
+// field. The items can be wrapped by [TableItems], along with options for displaying
+// them. The returned numeric slice marks the columns which hold only numbers.
+func buildTable(items any, columns []string) ([][]string, []bool, error) {
+	options := &tableOptions{}
+	if wrapped, ok := items.(tableItems); ok {
+		items = wrapped.items
+		for _, o := range wrapped.options {
+			o(options)
+		}
+	}
+
+	if rows, ok := items.([][]string); ok {
+		return stringTable(rows, columns, options)
+	}
+
+	v := indirect(reflect.ValueOf(items))
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Slice, reflect.Array:
+	case reflect.Struct, reflect.Map:
+		return verticalTable(v, columns, options)
+	default:
+		return nil, nil, fmt.Errorf("expected items to be a slice, a struct or a map but was of type: %v", reflect.TypeOf(items))
+	}
+
+	if len(columns) == 0 {
+		columns = defaultColumns(v)
+	}
+
+	rows := [][]string{columns}
+	numeric := make([]bool, len(columns))
+	hasValues := make([]bool, len(columns))
+	for i := range numeric {
+		numeric[i] = true
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		item := indirect(v.Index(i))
+		switch item.Kind() { //nolint:exhaustive
+		case reflect.Struct, reflect.Map:
+		case reflect.Pointer, reflect.Interface:
+			// a nil item is rendered as an empty row
+			rows = append(rows, make([]string, len(columns)))
+			continue
+		default:
+			continue
+		}
+
+		row := make([]string, 0, len(columns))
+		for j, c := range columns {
+			cell, ok := tableCell(item, c)
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			value, isNumber := options.cell(c, cell)
+			if value != "" {
+				hasValues[j] = true
+				numeric[j] = numeric[j] && isNumber
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+
+	for i := range numeric {
+		numeric[i] = numeric[i] && hasValues[i]
+	}
+
+	return rows, numeric, nil
+}
+
+// stringTable returns the rows of a table passed as a [][]string, whose first row is the
+// header. When columns are passed, only the matching columns are kept, in their order. The
+// columns whose values are all numbers are marked as numeric, as in [buildTable].
+func stringTable(rows [][]string, columns []string, options *tableOptions) ([][]string, []bool, error) {
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("expected the table to have a header row")
+	}
+
+	indexes := make([]int, 0, len(rows[0]))
+	if len(columns) == 0 {
+		for i := range rows[0] {
+			indexes = append(indexes, i)
+		}
+	}
+	for _, c := range columns {
+		index := -1
+		for i, header := range rows[0] {
+			if header == c {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, nil, fmt.Errorf("the table does not have a %q column", c)
+		}
+		indexes = append(indexes, index)
+	}
+
+	numeric := make([]bool, len(indexes))
+	hasValues := make([]bool, len(indexes))
+	for k := range numeric {
+		numeric[k] = true
+	}
+
+	selected := make([][]string, 0, len(rows))
+	for j, r := range rows {
+		row := make([]string, 0, len(indexes))
+		for k, i := range indexes {
+			cell := ""
+			if i < len(r) {
+				cell = r[i]
+			}
+			if j > 0 {
+				if cell != "" {
+					hasValues[k] = true
+					numeric[k] = numeric[k] && isNumberText(cell)
+				}
+				cell, _ = options.cell(rows[0][i], reflect.ValueOf(cell))
+			}
+			row = append(row, cell)
+		}
+		selected = append(selected, row)
+	}
+
+	for k := range numeric {
+		numeric[k] = numeric[k] && hasValues[k]
+	}
+
+	return selected, numeric, nil
+}
+
+// isNumberText reports whether the text of a table cell is a finite decimal number.
+func isNumberText(s string) bool {
+	f, err := strconv.ParseFloat(s, 64)
+	return err == nil && !math.IsInf(f, 0) && !math.IsNaN(f)
+}
+
+// verticalTable returns the rows of a table for a single struct or map, with a row per
+// field, or per map entry, holding its name and its value.
+func verticalTable(v reflect.Value, columns []string, options *tableOptions) ([][]string, []bool, error) {
+	if len(columns) == 0 {
+		columns = defaultColumns(v)
+	}
+
+	rows := make([][]string, 0, len(columns))
+	for _, c := range columns {
+		value := ""
+		if cell, ok := tableCell(v, c); ok {
+			value, _ = options.cell(c, cell)
+		}
+		rows = append(rows, []string{c, value})
+	}
+
+	return rows, nil, nil
+}
+
+// defaultColumns returns the columns used when none are passed, i.e. the exported fields
+// of a struct, or the sorted keys of the maps. The value can be a single struct or map, or
+// a slice of them.
+func defaultColumns(v reflect.Value) []string {
+	if v.Kind() == reflect.Struct {
+		return tableColumns(v.Type(), "")
+	}
+
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		elemType := v.Type().Elem()
+		if elemType.Kind() == reflect.Pointer {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() == reflect.Struct {
+			return tableColumns(elemType, "")
+		}
+	}
+
+	var maps []reflect.Value
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Map:
+		maps = append(maps, v)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if item := indirect(v.Index(i)); item.Kind() == reflect.Map {
+				maps = append(maps, item)
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	var columns []string
+	for _, m := range maps {
+		for _, key := range m.MapKeys() {
+			name := fmt.Sprintf("%v", key.Interface())
+			if !seen[name] {
+				seen[name] = true
+				columns = append(columns, name)
+			}
+		}
+	}
+	sort.Strings(columns)
+
+	return columns
+}
+
+// tableCell returns the value of a struct field, see [tableField], or of a map entry
+// matching the column.
+func tableCell(item reflect.Value, column string) (reflect.Value, bool) {
+	if item.Kind() == reflect.Struct {
+		return tableField(item, column)
+	}
+
+	if item.Type().Key().Kind() == reflect.String {
+		value := item.MapIndex(reflect.ValueOf(column).Convert(item.Type().Key()))
+		return value, value.IsValid()
+	}
+
+	for _, key := range item.MapKeys() {
+		if fmt.Sprintf("%v", key.Interface()) == column {
+			return item.MapIndex(key), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// indirect dereferences the pointers and interfaces, up to a nil one.
+func indirect(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// tableColumns returns the column names of the exported fields of a struct type, with the
+// fields of the nested structs listed by their path, e.g. "Address.City". Fields tagged
+// with `gospec:"-"` are left out.
+func tableColumns(typ reflect.Type, prefix string) []string {
+	var columns []string
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("gospec"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		if nested, ok := nestedStruct(f.Type); ok {
+			if f.Anonymous {
+				columns = append(columns, tableColumns(nested, prefix)...)
+			} else {
+				columns = append(columns, tableColumns(nested, prefix+name+".")...)
+			}
+			continue
+		}
+		columns = append(columns, prefix+name)
+	}
+	return columns
+}
+
+// tableField returns the value of the exported struct field matching the column, either by
+// its `gospec` tag or by its name. The fields of the embedded structs are matched as if
+// they were fields of the struct itself, and the ones of the other nested structs by their
+// path, e.g. "Address.City".
+func tableField(v reflect.Value, column string) (reflect.Value, bool) {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, matches := f.Name, f.Name == column
+		if tag, ok := f.Tag.Lookup("gospec"); ok {
+			if tag == "-" {
+				continue
+			}
+			name, matches = tag, tag == column
+		}
+		if matches {
+			return v.Field(i), true
+		}
+
+		if _, ok := nestedStruct(f.Type); !ok {
+			continue
+		}
+
+		rest := column
+		if !f.Anonymous {
+			if !strings.HasPrefix(column, name+".") {
+				continue
+			}
+			rest = column[len(name)+1:]
+		}
+
+		nested := v.Field(i)
+		if nested.Kind() == reflect.Pointer {
+			if nested.IsNil() {
+				continue
+			}
+			nested = nested.Elem()
+		}
+		if field, ok := tableField(nested, rest); ok {
+			return field, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// nestedStruct reports whether the fields of a struct field type are rendered as columns
+// of their own. That's the case for structs, and pointers to structs, which are not
+// formatted as a single value, e.g. a [time.Time] or a [fmt.Stringer].
+func nestedStruct(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Implements(stringerType) || typ == timeType {
+		return nil, false
+	}
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == timeType || reflect.PointerTo(typ).Implements(stringerType) {
+		return nil, false
+	}
+	return typ, true
+}
+
+// formatTableValue returns the text of a table cell, and whether the value is a number.
+func formatTableValue(v reflect.Value) (string, bool) {
+	for {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			return "", false
+		}
+		if v.CanInterface() {
+			switch z := v.Interface().(type) {
+			case time.Time:
+				return z.Format(time.RFC3339), false
+			case fmt.Stringer:
+				return z.String(), false
+			}
+		}
+		if v.CanAddr() && v.Kind() == reflect.Struct && v.Addr().CanInterface() {
+			if z, ok := v.Addr().Interface().(fmt.Stringer); ok {
+				// the String method has a pointer receiver
+				return z.String(), false
+			}
+		}
+		if v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface {
+			break
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.String:
+		return v.String(), false
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), false
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%.2f", v.Float()), true
+	}
+
+	if v.CanInterface() {
+		return fmt.Sprintf("%v", v.Interface()), false
+	}
+	return "", false
+}
+
+// writeTable writes the table rows in the Gherkin data table format, with each
+// line prefixed by the given indentation. When escape is set, the cell values are
+// escaped so that the table can be read back by a Gherkin parser. The columns
+// marked as numeric are right-aligned.
+func writeTable(sb *strings.Builder, indent string, rows [][]string, numeric []bool, escape bool) {
+	if escape {
+		escaped := make([][]string, 0, len(rows))
+		for _, r := range rows {
+			row := make([]string, 0, len(r))
+			for _, cell := range r {
+				row = append(row, escapeTableCell(cell))
+			}
+			escaped = append(escaped, row)
+		}
+		rows = escaped
+	}
+
+	var columnWidths []int
+	for _, r := range rows {
+		for i, cell := range r {
+			if i >= len(columnWidths) {
+				columnWidths = append(columnWidths, 0)
+			}
+			if w := displayWidth(cell); w > columnWidths[i] {
+				columnWidths[i] = w
+			}
+		}
+	}
+
+	for _, r := range rows {
+		sb.WriteString(indent)
+		sb.WriteString("|")
+		for i, cell := range r {
+			padding := strings.Repeat(" ", columnWidths[i]-displayWidth(cell))
+			if i < len(numeric) && numeric[i] {
+				sb.WriteString(" " + padding + cell + " ")
+			} else {
+				sb.WriteString(" " + cell + padding + " ")
+			}
+			sb.WriteString("|")
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// displayWidth returns the number of terminal columns the text takes, whereby the wide
+// East Asian characters and most emoji take two columns, and the combining marks none.
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case r < utf8.RuneSelf:
+			width++
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		case isWideRune(r):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+func isWideRune(r rune) bool {
+	return r >= 0x1100 && r <= 0x115F || // Hangul Jamo
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F || // CJK ... Yi
+		r >= 0xAC00 && r <= 0xD7A3 || // Hangul Syllables
+		r >= 0xF900 && r <= 0xFAFF || // CJK Compatibility Ideographs
+		r >= 0xFE30 && r <= 0xFE4F || // CJK Compatibility Forms
+		r >= 0xFF00 && r <= 0xFF60 || // Fullwidth Forms
+		r >= 0xFFE0 && r <= 0xFFE6 ||
+		r >= 0x1F300 && r <= 0x1F64F || // Pictographs and Emoticons
+		r >= 0x1F900 && r <= 0x1F9FF || // Supplemental Symbols and Pictographs
+		r >= 0x20000 && r <= 0x3FFFD // CJK Extensions
+}
+
+func escapeTableCell(cell string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`|`, `\|`,
+		"\n", `\n`,
+	).Replace(cell)
+}
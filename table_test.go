@@ -0,0 +1,252 @@
+package gospec
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+type inventoryLevel int
+
+func (l inventoryLevel) String() string {
+	return [...]string{"low", "high"}[l]
+}
+
+type inventoryAddress struct {
+	City string
+}
+
+type InventoryAudit struct {
+	CreatedAt time.Time
+}
+
+type inventoryItem struct {
+	InventoryAudit
+	Name     string `gospec:"Product name"`
+	Price    float64
+	Stock    uint
+	Discount *int
+	InStock  bool
+	Level    inventoryLevel
+	Delivery time.Duration
+	Address  inventoryAddress
+	Internal string `gospec:"-"`
+}
+
+func TestBuildTable(t *testing.T) {
+	discount := 10
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	rows, numeric, err := buildTable([]*inventoryItem{
+		{
+			InventoryAudit: InventoryAudit{CreatedAt: createdAt},
+			Name:           "Gopher toy",
+			Price:          14.99,
+			Stock:          7,
+			Discount:       &discount,
+			InStock:        true,
+			Level:          1,
+			Delivery:       48 * time.Hour,
+			Address:        inventoryAddress{City: "Sofia"},
+		},
+		nil,
+		{Name: "Crab toy", Price: 2.5},
+	}, nil)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]string{
+		{"CreatedAt", "Product name", "Price", "Stock", "Discount", "InStock", "Level", "Delivery", "Address.City"},
+		{"2024-01-02T03:04:05Z", "Gopher toy", "14.99", "7", "10", "true", "high", "48h0m0s", "Sofia"},
+		{"", "", "", "", "", "", "", "", ""},
+		{"0001-01-01T00:00:00Z", "Crab toy", "2.50", "0", "", "false", "low", "0s", ""},
+	}, rows)
+	assert.Equal(t, []bool{false, false, true, true, true, false, false, false, false}, numeric)
+
+	rows, numeric, err = buildTable([]inventoryItem{{Name: "Gopher toy", Price: 14.99}}, []string{"Product name", "Price", "price", "Unknown"})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]string{
+		{"Product name", "Price", "price", "Unknown"},
+		{"Gopher toy", "14.99", "", ""},
+	}, rows)
+	assert.Equal(t, []bool{false, true, false, false}, numeric)
+}
+
+func TestWriteTableAlignsNumbersAndWideCharacters(t *testing.T) {
+	var sb strings.Builder
+
+	writeTable(&sb, "  ", [][]string{
+		{"Name", "Price"},
+		{"玩具", "1.99"},
+		{"Crab toy", "17.49"},
+	}, []bool{false, true}, false)
+
+	assert.Equal(t, strings.Join([]string{
+		`  | Name     | Price |`,
+		`  | 玩具     |  1.99 |`,
+		`  | Crab toy | 17.49 |`,
+		``,
+	}, "\n"), sb.String())
+}
+
+func TestBuildTableFromMapsAndStrings(t *testing.T) {
+	rows, numeric, err := buildTable([]map[string]any{
+		{"Name": "Gopher toy", "Price": 14.99},
+		{"Name": "Crab toy", "Price": 2.5, "Tag": "sale"},
+	}, nil)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]string{
+		{"Name", "Price", "Tag"},
+		{"Gopher toy", "14.99", ""},
+		{"Crab toy", "2.50", "sale"},
+	}, rows)
+	assert.Equal(t, []bool{false, true, false}, numeric)
+
+	rows, numeric, err = buildTable([][]string{
+		{"Name", "Price", "Tag", "Stock"},
+		{"Gopher toy", "14.99", "", "7"},
+		{"Crab toy", "", "", "NaN"},
+	}, []string{"Price", "Name", "Tag", "Stock"})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]string{
+		{"Price", "Name", "Tag", "Stock"},
+		{"14.99", "Gopher toy", "", "7"},
+		{"", "Crab toy", "", "NaN"},
+	}, rows)
+	assert.Equal(t, []bool{true, false, false, false}, numeric)
+
+	_, _, err = buildTable([][]string{{"Name"}}, []string{"Price"})
+
+	assert.Equal(t, `the table does not have a "Price" column`, err.Error())
+
+	_, _, err = buildTable(42, nil)
+
+	assert.Equal(t, "expected items to be a slice, a struct or a map but was of type: int", err.Error())
+}
+
+func TestBuildVerticalTable(t *testing.T) {
+	rows, numeric, err := buildTable(&inventoryItem{Name: "Gopher toy", Price: 14.99, Address: inventoryAddress{City: "Sofia"}}, []string{"Product name", "Price", "Address.City"})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]string{
+		{"Product name", "Gopher toy"},
+		{"Price", "14.99"},
+		{"Address.City", "Sofia"},
+	}, rows)
+	assert.Equal(t, []bool(nil), numeric)
+
+	rows, _, err = buildTable(map[string]int{"retries": 3, "timeout": 30}, nil)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]string{
+		{"retries", "3"},
+		{"timeout", "30"},
+	}, rows)
+}
+
+func TestTableMustBeCalledInsideAStep(t *testing.T) {
+	var (
+		out bytes.Buffer
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, given, _, _, table := s.With(Output(&out)).API()
+
+			feature("Config", func() {
+				scenario("loading the defaults", func() {
+					table(map[string]int{"retries": 3}, "retries")
+
+					given("the default config", func(t *T) {
+						table(map[string]int{"retries": 3})
+					})
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any{
+		{"invalid position for `Table` function, it must be called inside a `Given`, `When` or `Then` step"},
+	}, tm.calls)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Config`,
+		``,
+		`  ✔ Scenario: loading the defaults`,
+		`    ✔ Given the default config`,
+		`      | retries | 3 |`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestTableOptions(t *testing.T) {
+	rows, numeric, err := buildTable(TableItems([]inventoryItem{
+		{Name: "Gopher toy with a very long name", Price: 14.9876, Stock: 7},
+		{Name: "Crab toy", Price: 2.5},
+	},
+		ColumnFormat("Price", "%.4f"),
+		ColumnFormatter("Stock", func(value any) string { return fmt.Sprintf("%d pcs", value) }),
+		MaxWidth(12, "Product name"),
+	), []string{"Product name", "Price", "Stock"})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]string{
+		{"Product name", "Price", "Stock"},
+		{"Gopher toy …", "14.9876", "7 pcs"},
+		{"Crab toy", "2.5000", "0 pcs"},
+	}, rows)
+	assert.Equal(t, []bool{false, true, true}, numeric)
+
+	rows, _, err = buildTable(TableItems([][]string{
+		{"Name", "Note"},
+		{"Gopher toy", "玩具玩具"},
+	}, MaxWidth(4)), nil)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]string{
+		{"Name", "Note"},
+		{"Gop…", "玩…"},
+	}, rows)
+}
+
+func TestTableWithOptions(t *testing.T) {
+	var (
+		out bytes.Buffer
+		tm  = &mock{t: t}
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			s.t = tm
+			feature, _, scenario, given, _, _, table := s.With(Output(&out)).API()
+
+			feature("Pricing", func() {
+				scenario("converting the prices", func() {
+					given("the following prices", func(t *T) {
+						table(TableItems(map[string]float64{"EUR": 1, "USD": 1.0842}, ColumnFormat("USD", "%.4f")))
+					})
+				})
+			})
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, strings.Join([]string{
+		`Feature: Pricing`,
+		``,
+		`  ✔ Scenario: converting the prices`,
+		`    ✔ Given the following prices`,
+		`      | EUR | 1.00   |`,
+		`      | USD | 1.0842 |`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
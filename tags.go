@@ -0,0 +1,164 @@
+package gospec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tagsEnv is the name of the environment variable holding the tag expression which
+// selects the scenarios to run, e.g. `@smoke and not @wip`. The scenarios which are
+// not selected get reported as skipped.
+const tagsEnv = "GOSPEC_TAGS"
+
+// tagExpression reports whether a set of tags matches the expression.
+type tagExpression func(tags []string) bool
+
+// parseTagExpression parses a tag expression, which is made of tags combined with
+// the `and`, `or` and `not` operators and grouped with parentheses. An empty
+// expression matches everything.
+func parseTagExpression(expression string) (tagExpression, error) {
+	p := &tagParser{tokens: tokenizeTagExpression(expression)}
+	if len(p.tokens) == 0 {
+		return func([]string) bool { return true }, nil
+	}
+
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in tag expression %q", p.tokens[p.pos], expression)
+	}
+
+	return expr, nil
+}
+
+func tokenizeTagExpression(expression string) []string {
+	expression = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+	return strings.Fields(expression)
+}
+
+type tagParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *tagParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *tagParser) or() (tagExpression, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "or" {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(tags []string) bool { return l(tags) || right(tags) }
+	}
+
+	return left, nil
+}
+
+func (p *tagParser) and() (tagExpression, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "and" {
+		p.pos++
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(tags []string) bool { return l(tags) && right(tags) }
+	}
+
+	return left, nil
+}
+
+func (p *tagParser) not() (tagExpression, error) {
+	if p.peek() != "not" {
+		return p.primary()
+	}
+
+	p.pos++
+	expr, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(tags []string) bool { return !expr(tags) }, nil
+}
+
+func (p *tagParser) primary() (tagExpression, error) {
+	token := p.peek()
+	p.pos++
+
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of tag expression")
+	case token == "(":
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("expected `)` in tag expression")
+		}
+		p.pos++
+		return expr, nil
+	case strings.HasPrefix(token, "@") && len(token) > 1:
+		return func(tags []string) bool {
+			for _, t := range tags {
+				if t == token {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q in tag expression, expected a tag starting with `@`", token)
+}
+
+// validateTags makes sure the tags start with `@` and contain no whitespace, so
+// that they can be rendered on a single tag line.
+func validateTags(tags []string) error {
+	for _, t := range tags {
+		if len(t) < 2 || !strings.HasPrefix(t, "@") || strings.ContainsAny(t, " \t\r\n") {
+			return fmt.Errorf("invalid tag %q, expected it to start with `@` and contain no whitespace", t)
+		}
+	}
+	return nil
+}
+
+// suiteTags returns the tags of a flattened suite, i.e. the tags of the scenario
+// along with the ones inherited from the feature.
+func suiteTags(suite []*featureStep) []string {
+	var tags []string
+	for _, s := range suite {
+		tags = append(tags, s.tags...)
+	}
+	return tags
+}
+
+// writeTags writes the tag line above a keyword.
+func writeTags(sb *strings.Builder, indent string, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+	sb.WriteString(indent + strings.Join(tags, " ") + "\n")
+}
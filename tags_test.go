@@ -0,0 +1,118 @@
+package gospec
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestTagExpressions(t *testing.T) {
+	testCases := []struct {
+		expression string
+		tags       []string
+		expected   bool
+	}{
+		{expression: "", tags: nil, expected: true},
+		{expression: "@smoke", tags: []string{"@smoke"}, expected: true},
+		{expression: "@smoke", tags: []string{"@wip"}, expected: false},
+		{expression: "not @wip", tags: nil, expected: true},
+		{expression: "@smoke and not @wip", tags: []string{"@smoke", "@wip"}, expected: false},
+		{expression: "@smoke and not @wip", tags: []string{"@smoke"}, expected: true},
+		{expression: "@smoke or @fast and @wip", tags: []string{"@smoke"}, expected: true},
+		{expression: "(@smoke or @fast) and @wip", tags: []string{"@smoke"}, expected: false},
+		{expression: "not (@smoke or @fast)", tags: []string{"@slow"}, expected: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.expression, func(t *testing.T) {
+			expr, err := parseTagExpression(tc.expression)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.expected, expr(tc.tags))
+		})
+	}
+}
+
+func TestInvalidTagExpressions(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expected   string
+	}{
+		{expression: "smoke", expected: "unexpected \"smoke\" in tag expression, expected a tag starting with `@`"},
+		{expression: "@smoke and", expected: "unexpected end of tag expression"},
+		{expression: "(@smoke", expected: "expected `)` in tag expression"},
+		{expression: "@smoke @wip", expected: `unexpected "@wip" in tag expression "@smoke @wip"`},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.expression, func(t *testing.T) {
+			_, err := parseTagExpression(tc.expression)
+			assert.Equal(t, tc.expected, err.Error())
+		})
+	}
+}
+
+func TestScenariosAreSelectedByTags(t *testing.T) {
+	t.Setenv(tagsEnv, "@smoke and not @wip")
+
+	var (
+		out   bytes.Buffer
+		spec  *FeatureSuite
+		tm    = &mock{t: t}
+		calls []string
+	)
+
+	func() {
+		WithFeatureSuite(t, func(s *FeatureSuite) {
+			spec = s
+			s.t = tm
+			feature, _, scenario, given, _, _, _ := s.With(Output(&out)).API()
+
+			feature("Checkout", func() {
+				scenario("paying by card", func() {
+					given("a card", func(t *T) { calls = append(calls, "card") })
+				})
+				scenario("paying by voucher", func() {
+					given("a voucher", func(t *T) { calls = append(calls, "voucher") })
+				}, "@wip")
+			}, "@smoke")
+		})
+	}()
+
+	assert.Equal(t, [][]any(nil), tm.calls)
+	assert.Equal(t, []string{"card"}, calls)
+	assert.Equal(t, statusPassed, scenarioStatus(spec.suites[0][1]))
+	assert.Equal(t, statusSkipped, scenarioStatus(spec.suites[1][1]))
+	assert.Equal(t, strings.Join([]string{
+		`@smoke`,
+		`Feature: Checkout`,
+		``,
+		`  ✔ Scenario: paying by card`,
+		`    ✔ Given a card`,
+		``,
+		`  @wip`,
+		`  - Scenario: paying by voucher`,
+		`    - Given a voucher`,
+		``,
+		``,
+	}, "\n"), out.String())
+}
+
+func TestTagsAreValidated(t *testing.T) {
+	tm := &mock{t: t}
+
+	WithFeatureSuite(t, func(s *FeatureSuite) {
+		s.t = tm
+		feature, _, _, _, _, _, _ := s.API()
+
+		feature("Checkout", func() {}, "smoke")
+	})
+
+	assert.Equal(t, [][]any{
+		{"%s", errors.New("invalid tag \"smoke\", expected it to start with `@` and contain no whitespace")},
+	}, tm.calls)
+}
@@ -0,0 +1,24 @@
+@cart
+Feature: Cart
+  As a shopper
+  I want to keep items in a cart
+
+  Background:
+    Given an empty cart
+
+  # adding items
+  Scenario: adding items
+    When 2 "Gopher toy" items are added
+    And the following items are added:
+      | Name     | Quantity |
+      | Crab toy | 1        |
+    Then the cart has 3 items
+
+  @wip
+  Scenario: adding a note
+    Given a note:
+      """text
+      Please wrap
+        as a gift
+      """
+    Then the note has 2 lines
@@ -8,6 +8,7 @@ type testingInterface interface {
 	Skip(args ...any)
 	Errorf(format string, args ...interface{})
 	Fatalf(format string, args ...any)
+	Logf(format string, args ...any)
 	Failed() bool
 	Skipped() bool
 	Run(name string, f func(t *testing.T)) bool
@@ -0,0 +1,94 @@
+package gospec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// updateDocsEnv is the name of the environment variable which, when set to a non-empty
+// value, makes the outputs with the [Verify] option rewrite their files instead of
+// failing the test when the files are out of date.
+const updateDocsEnv = "GOSPEC_UPDATE_DOCS"
+
+type namedWriter interface {
+	Name() string
+}
+
+// write writes the rendered text to the output. For outputs with the [Verify] option
+// the text is compared against the contents of the output file instead, and the
+// test fails with a diff when they are different.
+func (o *output1) write(t testingInterface, text string) (int, error) {
+	if !o.verify {
+		return o.out.Write([]byte(text))
+	}
+
+	t.Helper()
+
+	name := o.out.(namedWriter).Name()
+
+	committed, err := os.ReadFile(name)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("failed to read %s: %s", name, err)
+		return 0, err
+	}
+
+	if string(committed) == text {
+		return 0, nil
+	}
+
+	if os.Getenv(updateDocsEnv) != "" {
+		if err = os.WriteFile(name, []byte(text), 0o600); err != nil {
+			t.Errorf("failed to update %s: %s", name, err)
+			return 0, err
+		}
+		return len(text), nil
+	}
+
+	t.Errorf("%s is out of date, run the tests with %s=1 to update it:\n%s", name, updateDocsEnv, diff(string(committed), text))
+
+	return 0, nil
+}
+
+// diff returns a line by line diff between the expected and the actual text, whereby
+// the lines only present in the expected text are prefixed with "-", and the ones
+// only present in the actual text are prefixed with "+".
+func diff(expected, actual string) string {
+	a := strings.Split(expected, "\n")
+	b := strings.Split(actual, "\n")
+
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString(fmt.Sprintf("  %s\n", a[i]))
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString(fmt.Sprintf("- %s\n", a[i]))
+			i++
+		default:
+			sb.WriteString(fmt.Sprintf("+ %s\n", b[j]))
+			j++
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,75 @@
+package gospec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/slavsan/gospec/internal/testing/helpers/assert"
+)
+
+func TestVerifyOutput(t *testing.T) {
+	run := func(t *testing.T, f *os.File) *mock {
+		t.Helper()
+		tm := &mock{t: t}
+		WithSpecSuite(t, func(s *SpecSuite) {
+			s.t = tm
+			describe, _, it := s.With(Output(f, Verify, Colorful, Durations)).API()
+
+			describe("Cart", func() {
+				it("is empty", func(t *T) {})
+			})
+		})
+		return tm
+	}
+
+	open := func(t *testing.T, contents string) *os.File {
+		t.Helper()
+		name := filepath.Join(t.TempDir(), "cart.spec")
+		if err := os.WriteFile(name, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.OpenFile(name, os.O_RDWR, 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = f.Close() })
+		return f
+	}
+
+	t.Run("passes when the file is up to date", func(t *testing.T) {
+		f := open(t, "Cart\n  ✔ is empty\n\n")
+
+		tm := run(t, f)
+
+		assert.Equal(t, [][]any(nil), tm.calls)
+	})
+
+	t.Run("fails with a diff when the file is out of date", func(t *testing.T) {
+		f := open(t, "Cart\n  ✔ is not empty\n\n")
+
+		tm := run(t, f)
+
+		assert.Equal(t, [][]any{{
+			"%s is out of date, run the tests with %s=1 to update it:\n%s",
+			f.Name(),
+			"GOSPEC_UPDATE_DOCS",
+			"  Cart\n-   ✔ is not empty\n+   ✔ is empty\n  \n  \n",
+		}}, tm.calls)
+
+		contents, _ := os.ReadFile(f.Name())
+		assert.Equal(t, "Cart\n  ✔ is not empty\n\n", string(contents))
+	})
+
+	t.Run("updates the file when requested", func(t *testing.T) {
+		t.Setenv("GOSPEC_UPDATE_DOCS", "1")
+		f := open(t, "Cart\n  ✔ is not empty\n\n")
+
+		tm := run(t, f)
+
+		assert.Equal(t, [][]any(nil), tm.calls)
+
+		contents, _ := os.ReadFile(f.Name())
+		assert.Equal(t, "Cart\n  ✔ is empty\n\n", string(contents))
+	})
+}
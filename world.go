@@ -1,10 +1,8 @@
 package gospec
 
 import (
-	"fmt"
-	"reflect"
-	"strconv"
-	"strings"
+	"bytes"
+	"log/slog"
 	"sync"
 	"testing"
 )
@@ -20,6 +18,9 @@ type World struct {
 	values             map[string]any
 	mu                 sync.Mutex
 	currentFeatureStep *featureStep
+	// output is the buffer capturing the output of the spec, when the [CaptureOutput]
+	// option is set.
+	output *bytes.Buffer
 }
 
 func newWorld() *World {
@@ -33,90 +34,26 @@ func newWorld() *World {
 // It does the same thing as the [Table] function, but It's used only in parallel tests
 // when an instance of a test-scoped [World] struct is passed to the callback.
 // The Table method is supposed to be used only in [FeatureSuite] tests.
-func (w *World) Table(fs *FeatureSuite, items any, columns ...string) { //nolint:gocognit,cyclop
+func (w *World) Table(fs *FeatureSuite, items any, columns ...string) {
 	w.t.Helper()
 
-	// TODO: validate table was called in valid call site
-
-	var sb strings.Builder
-	n := &node2{}
-
-	items2 := reflect.ValueOf(items)
-
-	if items2.Kind() != reflect.Slice {
-		w.t.Errorf("expected items to be of type slice but was of type: %v", reflect.TypeOf(items))
+	if w.currentFeatureStep == nil {
+		w.t.Errorf("invalid position for `Table` method, it must be called inside a `Given`, `When` or `Then` step")
 		return
 	}
 
-	columnWidths := make(map[string]int, items2.Len())
-	_ = columnWidths
-
-	for _, x := range columns {
-		columnWidths[x] = len(x)
+	rows, numeric, err := buildTable(items, columns)
+	if err != nil {
+		w.t.Errorf("%s", err)
+		return
 	}
 
-	var rows []map[string]string
-
-	for i := 0; i < items2.Len(); i++ {
-		item := items2.Index(i)
-		if item.Kind() == reflect.Struct {
-			row := map[string]string{}
-			v := reflect.Indirect(item)
-			for j := 0; j < v.NumField(); j++ {
-				name := v.Type().Field(j).Name
-				value := v.Field(j).Interface()
-				maxWidth, ok := columnWidths[name]
-				_ = maxWidth
-				if !ok {
-					continue
-				}
-				switch z := value.(type) {
-				case string:
-					if len(z) > maxWidth {
-						columnWidths[name] = len(z)
-					}
-					row[name] = z
-				case float64, float32:
-					ff := fmt.Sprintf("%.2f", z)
-					if len(ff) > maxWidth {
-						columnWidths[name] = len(ff)
-					}
-					row[name] = ff
-				case int, int8, int16, int32, int64:
-					ff := fmt.Sprintf("%d", z)
-					if len(ff) > maxWidth {
-						columnWidths[name] = len(ff)
-					}
-					row[name] = ff
-				}
-			}
-			rows = append(rows, row)
-		}
-	}
-	sb.WriteString(fmt.Sprintf("%s|", strings.Repeat(fs.indentStep, 3)))
-	for _, c := range columns {
-		sb.WriteString(fmt.Sprintf(" %-"+strconv.Itoa(columnWidths[c])+"s ", c))
-		sb.WriteString("|")
-	}
-	sb.WriteString("\n")
-
-	for i, r := range rows {
-		_ = r
-		if i != 0 {
-			sb.WriteString("\n")
-		}
-		sb.WriteString(fmt.Sprintf("%s|", strings.Repeat(fs.indentStep, 3)))
-		for _, c := range columns {
-			sb.WriteString(fmt.Sprintf(" %-"+strconv.Itoa(columnWidths[c])+"s ", r[c]))
-			_ = c
-
-			sb.WriteString("|")
-		}
-	}
+	n := &node2{}
 
 	s := &featureStep{
-		kind:  isTable,
-		title: sb.String(),
+		kind:    isTable,
+		rows:    rows,
+		numeric: numeric,
 	}
 
 	n.step = s
@@ -133,6 +70,21 @@ func (w *World) Table(fs *FeatureSuite, items any, columns ...string) { //nolint
 	w.currentFeatureStep.n.children = append(w.currentFeatureStep.n.children, n)
 }
 
+// Attach does the same thing as the [Attach] function, for the test-scoped *testing.T of the
+// World, i.e. it attaches the artifact to the `it` block or the step being run.
+func (w *World) Attach(name, mimeType string, data []byte) {
+	w.t.Helper()
+	Attach(w.t, name, mimeType, data)
+}
+
+// SlogHandler returns a text [slog.Handler] for the `it` block or the scenario the World
+// belongs to. Unlike the one returned by the [SlogHandler] function, its records get captured
+// only into the output of this spec, even when other specs run in parallel. Its records are
+// written to [os.Stderr] when the [CaptureOutput] option is not set.
+func (w *World) SlogHandler(opts *slog.HandlerOptions) slog.Handler {
+	return slog.NewTextHandler(worldWriter{w: w}, opts)
+}
+
 // Get retrieves a value provided a name. If there is no such
 // variable with such a name defined, an error will be reported.
 func (w *World) Get(name string) any {